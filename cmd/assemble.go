@@ -18,10 +18,15 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/interlynk-io/sbomasm/pkg/assemble"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
@@ -38,10 +43,14 @@ Basic Example:
 Advanced Example:
 	$ sbomasm generate > config.yaml (edit the config file to add your settings)
 	$ sbomasm assemble -c config.yaml -o final_sbom_cdx.json in-sbom1.json in-sbom2.json
+
+	# Discover the input sboms recursively in a build tree
+	$ sbomasm assemble -n "mega-app" -v "1.0.0" -t "application" --from-dir ./sboms --glob "*.cdx.json" --exclude "*test*"
 	`,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		fromDir, _ := cmd.Flags().GetString("from-dir")
+		if len(args) == 0 && fromDir == "" {
 			return fmt.Errorf("please provide at least one sbom file to assemble")
 		}
 
@@ -64,7 +73,7 @@ Advanced Example:
 		// Populate the config object
 		config, err := assemble.PopulateConfig(assembleParams)
 		if err != nil {
-			fmt.Println("Error populating config:", err)
+			return err
 		}
 		return assemble.Assemble(config)
 	},
@@ -72,7 +81,7 @@ Advanced Example:
 
 func init() {
 	rootCmd.AddCommand(assembleCmd)
-	assembleCmd.Flags().StringP("output", "o", "", "path to assembled sbom, defaults to stdout")
+	assembleCmd.Flags().StringP("output", "o", "", "path to assembled sbom or an existing directory to auto-name it in, defaults to stdout")
 	assembleCmd.Flags().StringP("configPath", "c", "", "path to config file")
 
 	assembleCmd.Flags().StringP("name", "n", "", "name of the assembled sbom")
@@ -86,7 +95,7 @@ func init() {
 	assembleCmd.MarkFlagsMutuallyExclusive("flatMerge", "hierMerge", "assemblyMerge")
 
 	assembleCmd.Flags().BoolP("outputSpecCdx", "g", true, "output in cdx format")
-	assembleCmd.Flags().BoolP("outputSpecSpdx", "s", false, "output in spdx format")
+	assembleCmd.Flags().BoolP("outputSpecSpdx", "s", false, "output in spdx format, cyclonedx inputs are converted to spdx 2.3")
 	assembleCmd.MarkFlagsMutuallyExclusive("outputSpecCdx", "outputSpecSpdx")
 
 	assembleCmd.Flags().StringP("outputSpecVersion", "e", "", "spec version of the output sbom")
@@ -94,6 +103,18 @@ func init() {
 	assembleCmd.Flags().BoolP("xml", "x", false, "output in xml format")
 	assembleCmd.Flags().BoolP("json", "j", true, "output in json format")
 	assembleCmd.MarkFlagsMutuallyExclusive("xml", "json")
+
+	assembleCmd.Flags().String("annotation", "", "annotation recording how the sbom was assembled e.g 'merged by CI job https://ci.example.com/123'")
+	assembleCmd.Flags().StringArray("property", []string{}, "property to add to the assembled sbom as name=value e.g 'ci:job=https://ci.example.com/123'")
+	assembleCmd.Flags().String("timestamp", "", "timestamp of the assembled sbom: now (default), keep for the latest input timestamp or an RFC3339 value e.g 2024-01-02T15:04:05Z")
+	assembleCmd.Flags().Bool("strip-files", false, "spdx only: drop files, snippets and their relationships keeping only packages")
+	assembleCmd.Flags().Bool("continue-on-error", false, "skip input sboms which fail to load, exits with code 2 listing the skipped inputs")
+	assembleCmd.Flags().Bool("plan", false, "print the components each input contributes, matches and conflicts without writing the assembled sbom")
+	addLimitFlags(assembleCmd)
+
+	assembleCmd.Flags().String("from-dir", "", "directory to recursively discover input sboms in")
+	assembleCmd.Flags().String("glob", "*.json", "file name pattern of the sboms discovered with --from-dir")
+	assembleCmd.Flags().StringSlice("exclude", []string{}, "file name or relative path pattern of the sboms to skip with --from-dir")
 }
 
 func validatePath(path string) error {
@@ -109,6 +130,96 @@ func validatePath(path string) error {
 	return nil
 }
 
+// discoverInputs walks dir and returns the sorted files whose name matches glob,
+// skipping the ones whose name or path relative to dir matches an exclude pattern
+// and the output, which is a file or a directory of previously assembled sboms.
+func discoverInputs(dir, glob string, excludes []string, output string) ([]string, error) {
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("path %s is not a directory", dir)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	absOutput, outputIsDir := "", false
+	if output != "" {
+		if absOutput, err = filepath.Abs(output); err != nil {
+			return nil, err
+		}
+		stat, err := os.Stat(output)
+		outputIsDir = (err == nil && stat.IsDir()) || strings.HasSuffix(output, string(os.PathSeparator))
+	}
+
+	if outputIsDir && isWithin(absDir, absOutput) {
+		return nil, fmt.Errorf("output directory %s contains the input directory %s, write the assembled sbom outside of it", output, dir)
+	}
+
+	for _, p := range append([]string{glob}, excludes...) {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", p, err)
+		}
+	}
+
+	files := []string{}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if outputIsDir && abs == absOutput {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if ok, _ := filepath.Match(glob, d.Name()); !ok {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		excluded := lo.SomeBy(excludes, func(p string) bool {
+			nameMatch, _ := filepath.Match(p, d.Name())
+			relMatch, _ := filepath.Match(p, rel)
+			return nameMatch || relMatch
+		})
+
+		if excluded || abs == absOutput {
+			return nil
+		}
+
+		files = append(files, filepath.Clean(path))
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// isWithin reports whether path is dir or one of its descendants, both paths
+// are absolute.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
 func extractArgs(cmd *cobra.Command, args []string) (*assemble.Params, error) {
 	aParams := assemble.NewParams()
 
@@ -156,22 +267,55 @@ func extractArgs(cmd *cobra.Command, args []string) (*assemble.Params, error) {
 		aParams.Json = false
 	}
 
-	specVersion, _ := cmd.Flags().GetString("outputSpecVersion")
-	aParams.OutputSpecVersion = specVersion
+	// an unset version keeps the default of the output spec
+	if cmd.Flags().Changed("outputSpecVersion") {
+		aParams.OutputSpecVersion, _ = cmd.Flags().GetString("outputSpecVersion")
+	}
 
-	cdx, _ := cmd.Flags().GetBool("outputSpecCdx")
+	// the spec from the config file is kept unless -s or -g is passed,
+	// outputSpecCdx defaults to true so the spdx flag decides the spec
+	if cmd.Flags().Changed("outputSpecSpdx") || cmd.Flags().Changed("outputSpecCdx") {
+		spdx, _ := cmd.Flags().GetBool("outputSpecSpdx")
 
-	if cdx {
-		aParams.OutputSpec = "cyclonedx"
-	} else {
-		aParams.OutputSpec = "spdx"
+		if spdx {
+			aParams.OutputSpec = "spdx"
+		} else {
+			aParams.OutputSpec = "cyclonedx"
+		}
 	}
 
+	aParams.InputFormat, aParams.Limits = extractLimitFlags(cmd)
+	aParams.Plan, _ = cmd.Flags().GetBool("plan")
+	aParams.ContinueOnError, _ = cmd.Flags().GetBool("continue-on-error")
+	aParams.Timestamp, _ = cmd.Flags().GetString("timestamp")
+	aParams.StripFiles, _ = cmd.Flags().GetBool("strip-files")
+	aParams.Annotation, _ = cmd.Flags().GetString("annotation")
+	aParams.Properties, _ = cmd.Flags().GetStringArray("property")
+
 	for _, arg := range args {
 		if err := validatePath(arg); err != nil {
 			return nil, err
 		}
 		aParams.Input = append(aParams.Input, arg)
 	}
+
+	fromDir, _ := cmd.Flags().GetString("from-dir")
+	if fromDir != "" {
+		glob, _ := cmd.Flags().GetString("glob")
+		excludes, _ := cmd.Flags().GetStringSlice("exclude")
+
+		// a previous output written into the tree is not an input
+		discovered, err := discoverInputs(fromDir, glob, excludes, output)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(discovered) == 0 {
+			return nil, fmt.Errorf("no sboms matching %s found in %s", glob, fromDir)
+		}
+
+		aParams.Input = lo.Uniq(append(aParams.Input, discovered...))
+	}
+
 	return aParams, nil
 }
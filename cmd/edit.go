@@ -5,6 +5,8 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/interlynk-io/sbomasm/pkg/edit"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
@@ -40,6 +42,15 @@ Advanced Example:
 
 	# Edit's an sbom to add multiple hashes to the primary component
 	$ sbomasm edit --subject primary-component --hash "MD5 (hash1)" --hash "SHA256 (hash2)" in-sbom-5.json
+
+	# Edit's an sbom to map license aliases like "Apache 2.0" to spdx ids across all components
+	$ sbomasm edit --subject document --normalize-licenses in-sbom-7.json
+
+	# Edit's an sbom to fill missing cpes of all components from their purls
+	$ sbomasm edit --subject document --cpe-from-purl in-sbom-8.json
+
+	# Edit's an sbom to clear a wrong cpe from the primary component
+	$ sbomasm edit --subject primary-component --remove cpe in-sbom-6.json
 	`,
 	SilenceUsage: true,
 	Args:         cobra.ExactArgs(1),
@@ -67,6 +78,7 @@ func init() {
 	rootCmd.AddCommand(editCmd)
 	// Output controls
 	editCmd.Flags().StringP("output", "o", "", "path to edited sbom, defaults to stdout")
+	addLimitFlags(editCmd)
 
 	// Edit locations
 	editCmd.Flags().String("subject", "document", "subject to edit (document, primary-component, component-name-version)")
@@ -76,6 +88,7 @@ func init() {
 	// Edit controls
 	editCmd.Flags().BoolP("missing", "m", false, "edit only missing fields")
 	editCmd.Flags().BoolP("append", "a", false, "append to field instead of replacing")
+	editCmd.Flags().StringSlice("remove", []string{}, "field to clear (purl, cpe, copyright, description, repository) e.g --remove cpe")
 
 	// Edit fields
 	editCmd.Flags().String("name", "", "name of the entity")
@@ -94,6 +107,8 @@ func init() {
 	editCmd.Flags().String("type", "", "type to add e.g 'application'")
 
 	editCmd.Flags().Bool("timestamp", false, "add created-at timestamp")
+	editCmd.Flags().Bool("cpe-from-purl", false, "derive a cpe 2.3 from the purl where the cpe is missing, applies to all components when subject is document")
+	editCmd.Flags().Bool("normalize-licenses", false, "map license aliases e.g 'Apache 2.0' to spdx ids, applies to all components when subject is document")
 }
 
 func extractEditArgs(cmd *cobra.Command, args []string) (*edit.EditParams, error) {
@@ -101,6 +116,7 @@ func extractEditArgs(cmd *cobra.Command, args []string) (*edit.EditParams, error
 
 	editParams.Input = args[0]
 	editParams.Output, _ = cmd.Flags().GetString("output")
+	editParams.InputFormat, editParams.Limits = extractLimitFlags(cmd)
 
 	subject, _ := cmd.Flags().GetString("subject")
 	editParams.Subject = subject
@@ -111,6 +127,27 @@ func extractEditArgs(cmd *cobra.Command, args []string) (*edit.EditParams, error
 	missing, _ := cmd.Flags().GetBool("missing")
 	editParams.Missing = missing
 
+	// a field flag swallows the next flag when it is given without a value
+	for _, field := range []string{"purl", "cpe", "copyright", "description", "repository"} {
+		value, _ := cmd.Flags().GetString(field)
+		if strings.HasPrefix(value, "--") {
+			return nil, fmt.Errorf("flag --%s is missing a value, got %s", field, value)
+		}
+	}
+
+	removeFields, _ := cmd.Flags().GetStringSlice("remove")
+	for _, field := range removeFields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if strings.HasPrefix(field, "--") {
+			return nil, fmt.Errorf("flag --remove is missing a field, got %s e.g --remove cpe", field)
+		}
+		editParams.RemoveFields = append(editParams.RemoveFields, field)
+	}
+	editParams.Remove = cmd.Flags().Changed("remove")
+
 	append, _ := cmd.Flags().GetBool("append")
 	editParams.Append = append
 
@@ -159,5 +196,11 @@ func extractEditArgs(cmd *cobra.Command, args []string) (*edit.EditParams, error
 	timestamp, _ := cmd.Flags().GetBool("timestamp")
 	editParams.Timestamp = timestamp
 
+	normalizeLicenses, _ := cmd.Flags().GetBool("normalize-licenses")
+	editParams.NormalizeLicenses = normalizeLicenses
+
+	cpeFromPurl, _ := cmd.Flags().GetBool("cpe-from-purl")
+	editParams.CpeFromPurl = cpeFromPurl
+
 	return editParams, nil
 }
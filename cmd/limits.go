@@ -0,0 +1,40 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"github.com/interlynk-io/sbomasm/pkg/limits"
+	"github.com/spf13/cobra"
+)
+
+// addLimitFlags registers the input loading flags shared by the commands
+// reading sboms, so the limits and format detection behave the same in each.
+func addLimitFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64("max-file-size", limits.DefaultMaxFileSizeMB, "largest input sbom in MB, 0 disables the limit")
+	cmd.Flags().Int("max-components", limits.DefaultMaxComponents, "largest number of components in an input sbom, 0 disables the limit")
+	cmd.Flags().String("input-format", "", "force the input file format instead of detecting it (json, xml, tag-value, yaml, rdf)")
+}
+
+// extractLimitFlags returns the values of the flags registered by addLimitFlags.
+func extractLimitFlags(cmd *cobra.Command) (string, limits.Limits) {
+	inputFormat, _ := cmd.Flags().GetString("input-format")
+
+	l := limits.Default()
+	l.MaxFileSizeMB, _ = cmd.Flags().GetInt64("max-file-size")
+	l.MaxComponents, _ = cmd.Flags().GetInt("max-components")
+
+	return inputFormat, l
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/interlynk-io/sbomasm/pkg/lint"
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "helps finding structural problems in an sbom",
+	Long: `The lint command reports structural problems of an existing SBOM: a missing primary component, dependency or
+relationship refs which do not match any element, duplicate bom-refs or spdx ids, components without a purl or cpe,
+invalid license ids or expressions and, for spdx, a document which describes no package or more than one.
+Each problem is reported as an error or a warning, lint exits with a non-zero code when any error is found.
+
+Usage
+	sbomasm lint [flags] <input-sbom-file>
+
+Basic Example:
+	# Lint an sbom before sharing it
+	$ sbomasm lint in-sbom.json
+	`,
+	SilenceUsage: true,
+	Args:         cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		debug, _ := cmd.Flags().GetBool("debug")
+		if debug {
+			logger.InitDebugLogger()
+		} else {
+			logger.InitProdLogger()
+		}
+
+		ctx := logger.WithLogger(context.Background())
+
+		lintParams := extractLintArgs(cmd, args)
+		lintParams.Ctx = &ctx
+
+		issues, err := lint.Lint(lintParams)
+		if err != nil {
+			return err
+		}
+
+		if errs := lint.Report(os.Stdout, issues); errs > 0 {
+			return fmt.Errorf("sbom %s has %d errors", lintParams.Input, errs)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	addLimitFlags(lintCmd)
+}
+
+func extractLintArgs(cmd *cobra.Command, args []string) *lint.LintParams {
+	lintParams := lint.NewLintParams()
+
+	lintParams.Input = args[0]
+	lintParams.InputFormat, lintParams.Limits = extractLimitFlags(cmd)
+
+	return lintParams
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/redact"
+	"github.com/spf13/cobra"
+)
+
+// redactCmd represents the redact command
+var redactCmd = &cobra.Command{
+	Use:   "redact",
+	Short: "helps stripping sensitive data from an sbom before sharing it",
+	Long: `The redact command removes sensitive data from an existing SBOM before it is shared outside the organization.
+It removes properties matching name patterns, urls pointing to internal hosts and optionally supplier contact emails,
+across the document metadata, components and services. A summary of what was redacted is logged, run with --debug
+to list every redacted value.
+
+Usage
+	sbomasm redact [flags] <input-sbom-file>
+
+Basic Example:
+	# Redact properties in the internal namespace
+	$ sbomasm redact --property "internal:*" -o shared-sbom.json in-sbom.json
+
+	# Redact repository and download urls hosted internally along with supplier emails
+	$ sbomasm redact --internal-host "*.corp.example.com" --internal-host "git.example.com" --supplier-emails in-sbom.json
+	`,
+	SilenceUsage: true,
+	Args:         cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		debug, _ := cmd.Flags().GetBool("debug")
+		if debug {
+			logger.InitDebugLogger()
+		} else {
+			logger.InitProdLogger()
+		}
+
+		ctx := logger.WithLogger(context.Background())
+
+		redactParams := extractRedactArgs(cmd, args)
+		redactParams.Ctx = &ctx
+
+		return redact.Redact(redactParams)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(redactCmd)
+	redactCmd.Flags().StringP("output", "o", "", "path to redacted sbom, defaults to stdout")
+	addLimitFlags(redactCmd)
+
+	redactCmd.Flags().StringSlice("property", []string{}, "pattern of property names to remove e.g 'internal:*'")
+	redactCmd.Flags().StringSlice("internal-host", []string{}, "pattern of internal hosts whose urls are removed e.g '*.corp.example.com'")
+	redactCmd.Flags().Bool("supplier-emails", false, "remove supplier contact emails")
+}
+
+func extractRedactArgs(cmd *cobra.Command, args []string) *redact.RedactParams {
+	redactParams := redact.NewRedactParams()
+
+	redactParams.Input = args[0]
+	redactParams.Output, _ = cmd.Flags().GetString("output")
+	redactParams.InputFormat, redactParams.Limits = extractLimitFlags(cmd)
+
+	redactParams.Properties, _ = cmd.Flags().GetStringSlice("property")
+	redactParams.InternalHosts, _ = cmd.Flags().GetStringSlice("internal-host")
+	redactParams.SupplierEmails, _ = cmd.Flags().GetBool("supplier-emails")
+
+	return redactParams
+}
@@ -17,11 +17,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/google/go-github/v52/github"
+	"github.com/interlynk-io/sbomasm/pkg/assemble"
 	"github.com/spf13/cobra"
 	version "sigs.k8s.io/release-utils/version"
 )
@@ -46,10 +48,24 @@ func Execute() {
 
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
+// exitCode maps the error of a command to the process exit code, 2 when the
+// sbom was assembled but some inputs were skipped and 1 for any other error.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var partial *assemble.PartialMergeError
+	if errors.As(err, &partial) {
+		return 2
+	}
+	return 1
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
@@ -0,0 +1,47 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/interlynk-io/sbomasm/pkg/assemble"
+)
+
+func TestExitCode(t *testing.T) {
+	partial := &assemble.PartialMergeError{Total: 3, Skipped: []assemble.SkippedInput{{Reason: "sbom big.cdx.json is too large"}}}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"no error", nil, 0},
+		{"hard failure", errors.New("assembly requires more than one sbom file"), 1},
+		{"partial merge", partial, 2},
+		{"wrapped partial merge", fmt.Errorf("assemble: %w", partial), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package e2e_edit_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/rogpeppe/go-internal/testscript"
+	"github.com/samber/lo"
+	spdx_json "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx"
+)
+
+func TestSbomasmAssemble(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	t.Parallel()
+	testscript.Run(t, testscript.Params{
+		Dir:                 "testdata/assemble",
+		RequireExplicitExec: true,
+		Setup: func(env *testscript.Env) error {
+			// the release check needs network access
+			env.Setenv("INTERLYNK_DISABLE_VERSION_CHECK", "1")
+			return nil
+		},
+		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
+			"cdxdoc":  cdxdoc,
+			"pad":     pad,
+			"spdxdoc": spdxdoc,
+			"status":  status,
+		},
+	})
+}
+
+// status runs a program like exec and fails unless it exits with the given
+// code, its output can be checked with stdout and stderr afterwards.
+func status(ts *testscript.TestScript, neg bool, args []string) {
+	if neg || len(args) < 2 {
+		ts.Fatalf("usage: status code program [args...]")
+	}
+
+	want, err := strconv.Atoi(args[0])
+	ts.Check(err)
+
+	got := 0
+	var exitErr *exec.ExitError
+	if err := ts.Exec(args[1], args[2:]...); errors.As(err, &exitErr) {
+		got = exitErr.ExitCode()
+	} else if err != nil {
+		ts.Fatalf("%s: %v", args[1], err)
+	}
+
+	if got != want {
+		ts.Fatalf("%s exited with %d, want %d", args[1], got, want)
+	}
+}
+
+// pad grows a json file to the given size in MB by indenting its first line,
+// keeping the document valid.
+func pad(ts *testscript.TestScript, neg bool, args []string) {
+	if neg || len(args) != 2 {
+		ts.Fatalf("usage: pad file size-mb")
+	}
+
+	size, err := strconv.Atoi(args[1])
+	ts.Check(err)
+
+	path := ts.MkAbs(args[0])
+	data, err := os.ReadFile(path)
+	ts.Check(err)
+
+	data = append(bytes.Repeat([]byte(" "), size<<20), data...)
+	ts.Check(os.WriteFile(path, data, 0o644))
+}
+
+// cdxdoc prints the components, properties, annotations and declarations of a
+// json cyclonedx sbom using component names instead of the generated bom-refs,
+// it fails when a reference does not match any component.
+func cdxdoc(ts *testscript.TestScript, neg bool, args []string) {
+	if neg || len(args) != 1 {
+		ts.Fatalf("usage: cdxdoc file")
+	}
+
+	f, err := os.Open(ts.MkAbs(args[0]))
+	ts.Check(err)
+	defer f.Close()
+
+	bom := new(cydx.BOM)
+	ts.Check(cydx.NewBOMDecoder(f, cydx.BOMFileFormatJSON).Decode(bom))
+
+	names := map[string]string{}
+	var index func(comps *[]cydx.Component)
+	index = func(comps *[]cydx.Component) {
+		for _, c := range lo.FromPtr(comps) {
+			names[c.BOMRef] = fmt.Sprintf("%s@%s", c.Name, c.Version)
+			index(c.Components)
+		}
+	}
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		index(&[]cydx.Component{*bom.Metadata.Component})
+	}
+	index(bom.Components)
+
+	name := func(ref string) string {
+		n, ok := names[ref]
+		if !ok {
+			ts.Fatalf("%s does not match any component", ref)
+		}
+		return n
+	}
+
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		fmt.Fprintf(ts.Stdout(), "primary %s\n", name(bom.Metadata.Component.BOMRef))
+	}
+	for _, p := range lo.FromPtr(bom.Properties) {
+		fmt.Fprintf(ts.Stdout(), "property %s=%s\n", p.Name, p.Value)
+	}
+	for _, a := range lo.FromPtr(bom.Annotations) {
+		for _, subject := range lo.FromPtr(a.Subjects) {
+			fmt.Fprintf(ts.Stdout(), "annotation %s %s\n", name(string(subject)), a.Text)
+		}
+	}
+	if bom.Declarations != nil {
+		for _, a := range lo.FromPtr(bom.Declarations.Assessors) {
+			fmt.Fprintf(ts.Stdout(), "assessor %s\n", a.BOMRef)
+		}
+		for _, c := range lo.FromPtr(bom.Declarations.Claims) {
+			fmt.Fprintf(ts.Stdout(), "claim %s target %s\n", c.BOMRef, name(string(c.Target)))
+		}
+	}
+}
+
+// spdxdoc prints the packages, files, snippets, relationships, annotations and
+// external document refs of a json spdx document using element names instead
+// of the generated ids, it fails when an id does not match any element.
+func spdxdoc(ts *testscript.TestScript, neg bool, args []string) {
+	if neg || len(args) != 1 {
+		ts.Fatalf("usage: spdxdoc file")
+	}
+
+	f, err := os.Open(ts.MkAbs(args[0]))
+	ts.Check(err)
+	defer f.Close()
+
+	doc, err := spdx_json.Read(f)
+	ts.Check(err)
+
+	names := map[spdx.ElementID]string{doc.SPDXIdentifier: "DOCUMENT"}
+	for _, pkg := range doc.Packages {
+		names[pkg.PackageSPDXIdentifier] = fmt.Sprintf("%s@%s", pkg.PackageName, pkg.PackageVersion)
+		for _, file := range pkg.Files {
+			names[file.FileSPDXIdentifier] = file.FileName
+		}
+	}
+	for _, file := range doc.Files {
+		names[file.FileSPDXIdentifier] = file.FileName
+	}
+	for _, snippet := range doc.Snippets {
+		names[snippet.SnippetSPDXIdentifier] = snippet.SnippetName
+	}
+
+	name := func(id spdx.DocElementID) string {
+		switch {
+		case id.SpecialID != "":
+			return id.SpecialID
+		case id.DocumentRefID != "":
+			return fmt.Sprintf("DocumentRef-%s:%s", id.DocumentRefID, id.ElementRefID)
+		}
+		n, ok := names[id.ElementRefID]
+		if !ok {
+			ts.Fatalf("%s does not match any element", id.ElementRefID)
+		}
+		return n
+	}
+	element := func(id spdx.ElementID) string {
+		return name(spdx.DocElementID{ElementRefID: id})
+	}
+
+	for _, ref := range doc.ExternalDocumentReferences {
+		fmt.Fprintf(ts.Stdout(), "external %s %s\n", ref.DocumentRefID, ref.URI)
+	}
+	for _, pkg := range doc.Packages {
+		fmt.Fprintf(ts.Stdout(), "package %s\n", element(pkg.PackageSPDXIdentifier))
+		for _, a := range pkg.Annotations {
+			fmt.Fprintf(ts.Stdout(), "annotation %s %s\n", element(pkg.PackageSPDXIdentifier), a.AnnotationComment)
+		}
+		for _, file := range pkg.Files {
+			fmt.Fprintf(ts.Stdout(), "file %s in %s\n", file.FileName, element(pkg.PackageSPDXIdentifier))
+		}
+	}
+	for _, file := range doc.Files {
+		fmt.Fprintf(ts.Stdout(), "file %s\n", file.FileName)
+	}
+	for _, snippet := range doc.Snippets {
+		fmt.Fprintf(ts.Stdout(), "snippet %s from %s\n", snippet.SnippetName, element(snippet.SnippetFromFileSPDXIdentifier))
+		for _, r := range snippet.Ranges {
+			element(r.StartPointer.FileSPDXIdentifier)
+			element(r.EndPointer.FileSPDXIdentifier)
+		}
+	}
+	for _, a := range doc.Annotations {
+		fmt.Fprintf(ts.Stdout(), "annotation DOCUMENT %s\n", a.AnnotationComment)
+	}
+	for _, r := range doc.Relationships {
+		fmt.Fprintf(ts.Stdout(), "relationship %s %s %s\n", name(r.RefA), r.Relationship, name(r.RefB))
+	}
+}
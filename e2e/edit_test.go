@@ -21,6 +21,9 @@ func TestSbomasmEdit(t *testing.T) {
 		Dir:                 "testdata/edit",
 		RequireExplicitExec: true,
 		Setup: func(env *testscript.Env) error {
+			// the release check needs network access
+			env.Setenv("INTERLYNK_DISABLE_VERSION_CHECK", "1")
+
 			// copy required files to the workspace
 			if err := copyFile("testdata/edit/photon-lite.spdx.json", filepath.Join(env.WorkDir, "photon-lite.spdx.json")); err != nil {
 				return err
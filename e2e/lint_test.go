@@ -0,0 +1,24 @@
+package e2e_edit_test
+
+import (
+	"testing"
+
+	"github.com/rogpeppe/go-internal/testscript"
+)
+
+func TestSbomasmLint(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	t.Parallel()
+	testscript.Run(t, testscript.Params{
+		Dir:                 "testdata/lint",
+		RequireExplicitExec: true,
+		Setup: func(env *testscript.Env) error {
+			// the release check needs network access
+			env.Setenv("INTERLYNK_DISABLE_VERSION_CHECK", "1")
+			return nil
+		},
+	})
+}
@@ -18,10 +18,13 @@ package cdx
 import (
 	"context"
 	"errors"
+	"os"
 	"strings"
 
 	cydx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/google/uuid"
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
 	"github.com/samber/lo"
 )
 
@@ -84,6 +87,16 @@ type Checksum struct {
 	Value     string
 }
 
+type Property struct {
+	Name  string
+	Value string
+}
+
+type SkippedInput struct {
+	File   string
+	Reason string
+}
+
 type app struct {
 	Name           string
 	Version        string
@@ -100,6 +113,13 @@ type app struct {
 
 type output struct {
 	FileFormat      string
+	Pretty          bool
+	Indent          string
+	SplitByType     bool
+	RootFilter      string
+	Annotation      string
+	Properties      []Property
+	Timestamp       string
 	Spec            string
 	SpecVersion     string
 	File            string
@@ -110,7 +130,9 @@ type output struct {
 }
 
 type input struct {
-	Files []string
+	Files  []string
+	Format detect.FileFormat
+	Limits limits.Limits
 }
 
 type assemble struct {
@@ -120,6 +142,9 @@ type assemble struct {
 	FlatMerge                  bool
 	HierarchicalMerge          bool
 	AssemblyMerge              bool
+
+	// Plan reports the contribution of each input instead of merging
+	Plan bool
 }
 
 type MergeSettings struct {
@@ -128,17 +153,48 @@ type MergeSettings struct {
 	Output   output
 	Input    input
 	Assemble assemble
+
+	// ContinueOnError skips the inputs which fail to load instead of
+	// failing the merge, the skipped inputs are listed in Skipped.
+	ContinueOnError bool
+	Skipped         []SkippedInput
 }
 
 func Merge(ms *MergeSettings) error {
-	if len(ms.Output.Spec) > 0 && ms.Output.Spec != "cyclonedx" {
+	if len(ms.Output.Spec) > 0 && ms.Output.Spec != "cyclonedx" && ms.Output.Spec != "spdx" {
 		return errors.New("invalid output spec")
 	}
 
-	if len(ms.Output.SpecVersion) > 0 && !validSpecVersion(ms.Output.SpecVersion) {
+	if ms.Output.Spec == "spdx" {
+		// cyclonedx inputs are merged as usual and converted to spdx on write
+		if len(ms.Output.SpecVersion) > 0 && ms.Output.SpecVersion != "2.3" {
+			return errors.New("invalid SPDX spec version")
+		}
+
+		if ms.Output.FileFormat == "xml" {
+			return errors.New("spdx output only supports json format")
+		}
+
+		if ms.Output.Upload {
+			return errors.New("spdx output cannot be uploaded to dependency track")
+		}
+
+		if ms.Output.SplitByType {
+			return errors.New("split by type is only supported for cyclonedx output")
+		}
+	} else if len(ms.Output.SpecVersion) > 0 && !validSpecVersion(ms.Output.SpecVersion) {
 		return errors.New("invalid CycloneDX spec version")
 	}
 
+	if ms.Output.SplitByType && (ms.Output.File == "" || ms.Output.Upload) {
+		return errors.New("split by type requires an output file")
+	}
+
 	merger := newMerge(ms)
+
+	if ms.Assemble.Plan {
+		return merger.plan(os.Stdout)
+	}
+
 	return merger.combinedMerge()
 }
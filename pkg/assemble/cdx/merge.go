@@ -17,15 +17,22 @@
 package cdx
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	cydx "github.com/CycloneDX/cyclonedx-go"
 	dtrack "github.com/DependencyTrack/client-go"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/interlynk-io/sbomasm/pkg/timestamp"
 	"github.com/samber/lo"
+	"sigs.k8s.io/release-utils/version"
 )
 
 type merge struct {
@@ -42,21 +49,46 @@ func newMerge(ms *MergeSettings) *merge {
 	}
 }
 
-func (m *merge) loadBoms() {
+func (m *merge) loadBoms() error {
+	log := logger.FromContext(*m.settings.Ctx)
+	loaded := []string{}
+
 	for _, path := range m.settings.Input.Files {
-		bom, err := loadBom(*m.settings.Ctx, path)
+		bom, err := sbom.LoadCdx(*m.settings.Ctx, path, m.settings.Input.Format, m.settings.Input.Limits)
 		if err != nil {
-			panic(err) // TODO: return error instead of panic
+			err = fmt.Errorf("unable to load sbom %s: %w", path, err)
 		}
+
+		if err != nil {
+			if !m.settings.ContinueOnError {
+				return err
+			}
+			log.Warnf("skipping input: %v", err)
+			m.settings.Skipped = append(m.settings.Skipped, SkippedInput{File: path, Reason: err.Error()})
+			continue
+		}
+
 		m.in = append(m.in, bom)
+		loaded = append(loaded, path)
+	}
+
+	if len(m.in) == 0 {
+		return fmt.Errorf("none of the %d input sboms could be loaded", len(m.settings.Input.Files))
 	}
+
+	// later steps refer to the inputs by index
+	m.settings.Input.Files = loaded
+
+	return nil
 }
 
 func (m *merge) combinedMerge() error {
 	log := logger.FromContext(*m.settings.Ctx)
 
 	log.Debug("loading sboms")
-	m.loadBoms()
+	if err := m.loadBoms(); err != nil {
+		return err
+	}
 
 	log.Debugf("initialize component service")
 	//cs := newComponentService(*m.settings.Ctx)
@@ -78,6 +110,16 @@ func (m *merge) combinedMerge() error {
 	toolsList := buildToolList(m.in)
 	log.Debugf("build a list of tools from each sbom found comps: %d, service: %d", len(*toolsList.Components), len(*toolsList.Services))
 
+	// build a list of formulas from each sbom
+	formulaList, err := buildFormulationList(*m.settings.Ctx, m.in, cs)
+	if err != nil {
+		return err
+	}
+	log.Debugf("build a list of formulas from each sbom found %d", len(formulaList))
+
+	// build the declarations from each sbom
+	declarations := buildDeclarations(*m.settings.Ctx, m.in, cs)
+
 	//Build the final sbom
 	log.Debugf("generating output sbom")
 	m.initOutBom()
@@ -88,6 +130,22 @@ func (m *merge) combinedMerge() error {
 	log.Debugf("assign tools to metadata")
 	m.out.Metadata.Tools = toolsList
 
+	if len(formulaList) > 0 {
+		m.out.Formulation = &formulaList
+
+		if m.predatesOutput(cydx.SpecVersion1_5) {
+			log.Warnf("formulation requires cyclonedx 1.5 or later and is dropped from the %s output", m.settings.Output.SpecVersion)
+		}
+	}
+
+	if declarations != nil {
+		m.out.Declarations = declarations
+
+		if m.predatesOutput(cydx.SpecVersion1_6) {
+			log.Warnf("declarations require cyclonedx 1.6 or later and are dropped from the %s output", m.settings.Output.SpecVersion)
+		}
+	}
+
 	if m.settings.Assemble.FlatMerge {
 		finalCompList := []cydx.Component{}
 		finalCompList = append(finalCompList, priCompList...)
@@ -164,6 +222,18 @@ func (m *merge) combinedMerge() error {
 		log.Debugf("hierarchical merge: final dependency list: %d", len(depList))
 	}
 
+	if m.settings.Output.RootFilter != "" {
+		log.Debugf("filtering to the subtree of %s", m.settings.Output.RootFilter)
+		if err := m.filterToRoot(cs); err != nil {
+			return err
+		}
+	}
+
+	if m.settings.Output.Annotation != "" || len(m.settings.Output.Properties) > 0 {
+		log.Debugf("adding provenance annotation and %d properties", len(m.settings.Output.Properties))
+		m.addProvenance()
+	}
+
 	// Writes sbom to file or uploads
 	log.Debugf("writing sbom")
 	return m.processSBOM()
@@ -174,7 +244,7 @@ func (m *merge) initOutBom() {
 	m.out.SerialNumber = newSerialNumber()
 
 	m.out.Metadata = &cydx.Metadata{}
-	m.out.Metadata.Timestamp = utcNowTime()
+	m.out.Metadata.Timestamp = m.outputTimestamp()
 
 	if m.settings.App.Supplier.Name != "" || m.settings.App.Supplier.Email != "" {
 		m.out.Metadata.Supplier = &cydx.OrganizationalEntity{}
@@ -204,6 +274,71 @@ func (m *merge) initOutBom() {
 	}
 }
 
+// addProvenance records how the sbom was assembled as bom level properties
+// and an annotation on the primary component made by sbomasm.
+func (m *merge) addProvenance() {
+	log := logger.FromContext(*m.settings.Ctx)
+
+	if len(m.settings.Output.Properties) > 0 {
+		props := lo.FromPtr(m.out.Properties)
+		for _, p := range m.settings.Output.Properties {
+			props = append(props, cydx.Property{Name: p.Name, Value: p.Value})
+		}
+		m.out.Properties = &props
+	}
+
+	if m.settings.Output.Annotation == "" {
+		return
+	}
+
+	m.out.Annotations = &[]cydx.Annotation{
+		{
+			BOMRef:   newBomRef(),
+			Subjects: &[]cydx.BOMReference{cydx.BOMReference(m.out.Metadata.Component.BOMRef)},
+			Annotator: &cydx.Annotator{
+				Component: &cydx.Component{
+					Type:    cydx.ComponentTypeApplication,
+					Name:    "sbomasm",
+					Version: version.GetVersionInfo().GitVersion,
+				},
+			},
+			Timestamp: m.out.Metadata.Timestamp,
+			Text:      m.settings.Output.Annotation,
+		},
+	}
+
+	if m.predatesOutput(cydx.SpecVersion1_5) {
+		log.Warnf("annotations require cyclonedx 1.5 or later and are dropped from the %s output", m.settings.Output.SpecVersion)
+	}
+}
+
+// predatesOutput reports whether the cyclonedx output version is older than
+// the version which introduced a field, such fields are dropped on write. The
+// spdx conversion does not carry them either way.
+func (m *merge) predatesOutput(introduced cydx.SpecVersion) bool {
+	if m.settings.Output.Spec == "spdx" || m.settings.Output.SpecVersion == "" {
+		return false
+	}
+	return specVersionMap[m.settings.Output.SpecVersion] < introduced
+}
+
+// outputTimestamp resolves the timestamp setting, now by default, keep for the
+// latest timestamp of the input sboms or a fixed RFC3339 value.
+func (m *merge) outputTimestamp() string {
+	ts := lo.FilterMap(m.in, func(bom *cydx.BOM, _ int) (string, bool) {
+		if bom.Metadata == nil {
+			return "", false
+		}
+		return bom.Metadata.Timestamp, bom.Metadata.Timestamp != ""
+	})
+
+	resolved, ok := timestamp.Resolve(m.settings.Output.Timestamp, ts)
+	if !ok {
+		logger.FromContext(*m.settings.Ctx).Warnf("input sboms have no valid timestamp to keep, using the current time")
+	}
+	return resolved
+}
+
 func (m *merge) setupPrimaryComp() *cydx.Component {
 	pc := cydx.Component{}
 
@@ -259,7 +394,9 @@ func (m *merge) processSBOM() error {
 	var output io.Writer
 	var sb strings.Builder
 
-	log := logger.FromContext(*m.settings.Ctx)
+	if m.settings.Output.Spec == "spdx" {
+		return m.processSpdxSBOM()
+	}
 
 	if m.settings.Output.Upload {
 		output = &sb
@@ -274,39 +411,186 @@ func (m *merge) processSBOM() error {
 		output = f
 	}
 
+	if err := m.encodeBom(output, m.out); err != nil {
+		return err
+	}
+
+	if m.settings.Output.SplitByType {
+		if err := m.writeTypeExtracts(); err != nil {
+			return err
+		}
+	}
+
+	if m.settings.Output.Upload {
+		return m.uploadToServer(sb.String())
+	}
+
+	return nil
+}
+
+func (m *merge) encodeBom(output io.Writer, bom *cydx.BOM) error {
+	log := logger.FromContext(*m.settings.Ctx)
+
+	// The cyclonedx encoder only indents with two spaces, a custom json
+	// indent is applied to the compact output after encoding.
+	var compact bytes.Buffer
+	customIndent := m.settings.Output.Pretty && m.settings.Output.Indent != "" && m.settings.Output.FileFormat != "xml"
+
+	encOutput := output
+	if customIndent {
+		encOutput = &compact
+	}
+
 	var encoder cydx.BOMEncoder
 	switch m.settings.Output.FileFormat {
 	case "xml":
 		log.Debugf("writing sbom in xml format")
-		encoder = cydx.NewBOMEncoder(output, cydx.BOMFileFormatXML)
+		encoder = cydx.NewBOMEncoder(encOutput, cydx.BOMFileFormatXML)
 	default:
 		log.Debugf("writing sbom in json format")
-		encoder = cydx.NewBOMEncoder(output, cydx.BOMFileFormatJSON)
+		encoder = cydx.NewBOMEncoder(encOutput, cydx.BOMFileFormatJSON)
 	}
 
-	encoder.SetPretty(true)
+	encoder.SetPretty(m.settings.Output.Pretty && !customIndent)
 	encoder.SetEscapeHTML(true)
 
 	var err error
 	if m.settings.Output.SpecVersion == "" {
-		err = encoder.Encode(m.out)
+		err = encoder.Encode(bom)
 	} else {
 		log.Debugf("writing sbom in version %s", m.settings.Output.SpecVersion)
 		outputVersion := specVersionMap[m.settings.Output.SpecVersion]
-		err = encoder.EncodeVersion(m.out, outputVersion)
+		err = encoder.EncodeVersion(bom, outputVersion)
 	}
 
 	if err != nil {
 		return err
 	}
 
-	if m.settings.Output.Upload {
-		return m.uploadToServer(sb.String())
+	if customIndent {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, compact.Bytes(), "", m.settings.Output.Indent); err != nil {
+			return err
+		}
+		if _, err := output.Write(indented.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTypeExtracts writes one sbom per component type next to the output file,
+// e.g. final.json produces final.library.json, final.application.json. Each extract
+// carries the merged metadata and a flat list of the components of that type.
+func (m *merge) writeTypeExtracts() error {
+	log := logger.FromContext(*m.settings.Ctx)
+
+	byType := make(map[cydx.ComponentType][]cydx.Component)
+	types := []cydx.ComponentType{}
+	seen := make(map[string]bool)
+
+	var collect func(comps *[]cydx.Component)
+	collect = func(comps *[]cydx.Component) {
+		for _, comp := range lo.FromPtr(comps) {
+			if comp.BOMRef != "" && seen[comp.BOMRef] {
+				continue
+			}
+			seen[comp.BOMRef] = true
+
+			nested := comp.Components
+			comp.Components = nil
+
+			if _, ok := byType[comp.Type]; !ok {
+				types = append(types, comp.Type)
+			}
+			byType[comp.Type] = append(byType[comp.Type], comp)
+
+			collect(nested)
+		}
+	}
+	// assembly merge nests the input primary components under the metadata component
+	collect(m.out.Metadata.Component.Components)
+	collect(m.out.Components)
+
+	pc := *m.out.Metadata.Component
+	pc.Components = nil
+
+	ext := filepath.Ext(m.settings.Output.File)
+	stem := strings.TrimSuffix(m.settings.Output.File, ext)
+
+	for _, typ := range types {
+		name := string(typ)
+		if name == "" {
+			name = "unknown"
+		}
+		path := fmt.Sprintf("%s.%s%s", stem, name, ext)
+
+		comps := byType[typ]
+		extract := cydx.NewBOM()
+		extract.SerialNumber = newSerialNumber()
+		extract.Metadata = &cydx.Metadata{
+			Timestamp: m.out.Metadata.Timestamp,
+			Tools:     m.out.Metadata.Tools,
+			Component: &pc,
+		}
+		extract.Components = &comps
+
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+
+		err = m.encodeBom(f, extract)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		log.Debugf("wrote %d %s components to %s", len(comps), name, path)
 	}
 
 	return nil
 }
 
+func (m *merge) processSpdxSBOM() error {
+	log := logger.FromContext(*m.settings.Ctx)
+
+	doc, err := toSpdxDocument(m.out)
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = os.Stdout
+	if m.settings.Output.File != "" {
+		f, err := os.Create(m.settings.Output.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		output = f
+	}
+
+	log.Debugf("writing sbom converted to spdx with packages:%d, relationships:%d", len(doc.Packages), len(doc.Relationships))
+
+	var buf []byte
+	if m.settings.Output.Pretty {
+		indent := " "
+		if m.settings.Output.Indent != "" {
+			indent = m.settings.Output.Indent
+		}
+		buf, err = json.MarshalIndent(doc, "", indent)
+	} else {
+		buf, err = json.Marshal(doc)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = output.Write(buf)
+	return err
+}
+
 func (m *merge) uploadToServer(bomContent string) error {
 	log := logger.FromContext(*m.settings.Ctx)
 
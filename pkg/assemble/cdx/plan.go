@@ -0,0 +1,100 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdx
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/samber/lo"
+)
+
+type planSeen struct {
+	input string
+	purl  string
+}
+
+// plan loads the input sboms and reports how many components each one
+// contributes to the merged sbom, how many match components of an earlier
+// sbom and which matches disagree on the purl. Nothing is merged or written.
+func (m *merge) plan(w io.Writer) error {
+	if err := m.loadBoms(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]planSeen)
+	var total, added, matched, conflicts int
+
+	fmt.Fprintf(w, "Assembly plan for %d cyclonedx sboms (%s)\n", len(m.in), m.mergeMode())
+
+	for i, bom := range m.in {
+		input := filepath.Base(m.settings.Input.Files[i])
+
+		comps := lo.FromPtr(bom.Components)
+		if bom.Metadata != nil && bom.Metadata.Component != nil {
+			comps = append([]cydx.Component{*bom.Metadata.Component}, comps...)
+		}
+
+		var inAdded, inMatched int
+		var inConflicts []string
+
+		for _, comp := range comps {
+			key := componentLookupKey(&comp)
+
+			found, ok := seen[key]
+			if !ok {
+				seen[key] = planSeen{input: input, purl: comp.PackageURL}
+				inAdded++
+				continue
+			}
+
+			inMatched++
+			if found.purl != "" && comp.PackageURL != "" && found.purl != comp.PackageURL {
+				inConflicts = append(inConflicts, fmt.Sprintf("%s@%s purl %s differs from %s in %s",
+					comp.Name, comp.Version, comp.PackageURL, found.purl, found.input))
+			}
+		}
+
+		fmt.Fprintf(w, "  %s: %d components, %d new, %d matched, %d conflicts\n",
+			input, len(comps), inAdded, inMatched, len(inConflicts))
+		for _, c := range inConflicts {
+			fmt.Fprintf(w, "    conflict: %s\n", c)
+		}
+
+		total += len(comps)
+		added += inAdded
+		matched += inMatched
+		conflicts += len(inConflicts)
+	}
+
+	fmt.Fprintf(w, "Total: %d components read, %d in the merged sbom, %d matched, %d conflicts\n",
+		total, added, matched, conflicts)
+
+	return nil
+}
+
+func (m *merge) mergeMode() string {
+	switch {
+	case m.settings.Assemble.FlatMerge:
+		return "flat merge"
+	case m.settings.Assemble.AssemblyMerge:
+		return "assembly merge"
+	}
+	return "hierarchical merge"
+}
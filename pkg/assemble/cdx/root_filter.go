@@ -0,0 +1,161 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdx
+
+import (
+	"fmt"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/samber/lo"
+)
+
+// filterToRoot prunes the merged bom to the component selected by the root
+// filter and everything reachable from it through dependencies or nested
+// components. The selected component becomes the metadata component.
+func (m *merge) filterToRoot(cs *uniqueComponentService) error {
+	log := logger.FromContext(*m.settings.Ctx)
+	filter := m.settings.Output.RootFilter
+
+	// the merge gave every component a new bom-ref, a filter naming an input
+	// bom-ref is matched against the merged id
+	ref := filter
+	if newID, ok := cs.ResolveDepID(filter); ok {
+		ref = newID
+	}
+
+	// with assembly merge the input primary components are nested under the
+	// metadata component instead of the component list
+	var primaryChildren *[]cydx.Component
+	if m.out.Metadata != nil && m.out.Metadata.Component != nil {
+		primaryChildren = m.out.Metadata.Component.Components
+	}
+
+	index := map[string]*cydx.Component{}
+	indexComponents(primaryChildren, index)
+	indexComponents(m.out.Components, index)
+
+	matches := lo.Filter(lo.Values(index), func(c *cydx.Component, _ int) bool {
+		return c.BOMRef == ref || c.PackageURL == filter || c.Name == filter ||
+			fmt.Sprintf("%s@%s", c.Name, c.Version) == filter
+	})
+
+	if len(matches) == 0 {
+		return fmt.Errorf("root filter %s does not match any component", filter)
+	}
+
+	if len(matches) > 1 {
+		return fmt.Errorf("root filter %s matches %d components, use a purl or bom-ref instead", filter, len(matches))
+	}
+
+	root := matches[0]
+
+	deps := map[string][]string{}
+	for _, d := range lo.FromPtr(m.out.Dependencies) {
+		deps[d.Ref] = append(deps[d.Ref], lo.FromPtr(d.Dependencies)...)
+	}
+
+	reachable := map[string]bool{}
+	queue := []string{root.BOMRef}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+
+		if reachable[ref] {
+			continue
+		}
+		reachable[ref] = true
+
+		queue = append(queue, deps[ref]...)
+		if c, ok := index[ref]; ok {
+			for _, child := range lo.FromPtr(c.Components) {
+				queue = append(queue, child.BOMRef)
+			}
+		}
+	}
+
+	// components nested under the root stay there, copies of them elsewhere
+	// in the tree are dropped
+	subtree := map[string]*cydx.Component{root.BOMRef: root}
+	indexComponents(root.Components, subtree)
+
+	// the metadata component is replaced by the root, its reachable children
+	// move to the component list
+	comps := pruneComponents(lo.FromPtr(primaryChildren), subtree, reachable)
+	comps = append(comps, pruneComponents(lo.FromPtr(m.out.Components), subtree, reachable)...)
+	m.out.Components = &comps
+
+	newRoot := *root
+	m.out.Metadata.Component = &newRoot
+
+	depList := []cydx.Dependency{}
+	for _, d := range lo.FromPtr(m.out.Dependencies) {
+		if !reachable[d.Ref] {
+			continue
+		}
+		refs := lo.Filter(lo.FromPtr(d.Dependencies), func(ref string, _ int) bool {
+			return reachable[ref]
+		})
+		depList = append(depList, cydx.Dependency{
+			Ref:          d.Ref,
+			Dependencies: &refs,
+		})
+	}
+	m.out.Dependencies = &depList
+
+	log.Debugf("root filter: %s selected %s, kept %d reachable components", filter, root.BOMRef, len(reachable))
+
+	return nil
+}
+
+func indexComponents(comps *[]cydx.Component, index map[string]*cydx.Component) {
+	for i := range lo.FromPtr(comps) {
+		c := &(*comps)[i]
+		if c.BOMRef != "" {
+			index[c.BOMRef] = c
+		}
+		indexComponents(c.Components, index)
+	}
+}
+
+// pruneComponents keeps the reachable components, reachable children of a
+// dropped component are moved up to its parent. The root and its subtree are
+// removed since they are moved to the metadata.
+func pruneComponents(comps []cydx.Component, subtree map[string]*cydx.Component, reachable map[string]bool) []cydx.Component {
+	kept := []cydx.Component{}
+
+	for _, c := range comps {
+		if _, ok := subtree[c.BOMRef]; ok {
+			continue
+		}
+
+		children := pruneComponents(lo.FromPtr(c.Components), subtree, reachable)
+
+		if !reachable[c.BOMRef] {
+			kept = append(kept, children...)
+			continue
+		}
+
+		c.Components = nil
+		if len(children) > 0 {
+			c.Components = &children
+		}
+		kept = append(kept, c)
+	}
+
+	return kept
+}
@@ -0,0 +1,284 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/spdx/tools-golang/spdx/v2/common"
+	"github.com/spdx/tools-golang/spdx/v2/v2_3"
+	"sigs.k8s.io/release-utils/version"
+)
+
+const spdxNOA = "NOASSERTION"
+
+var cdx_hash_algos_to_spdx = map[cydx.HashAlgorithm]common.ChecksumAlgorithm{
+	cydx.HashAlgoMD5:         common.MD5,
+	cydx.HashAlgoSHA1:        common.SHA1,
+	cydx.HashAlgoSHA256:      common.SHA256,
+	cydx.HashAlgoSHA384:      common.SHA384,
+	cydx.HashAlgoSHA512:      common.SHA512,
+	cydx.HashAlgoSHA3_256:    common.SHA3_256,
+	cydx.HashAlgoSHA3_384:    common.SHA3_384,
+	cydx.HashAlgoSHA3_512:    common.SHA3_512,
+	cydx.HashAlgoBlake2b_256: common.BLAKE2b_256,
+	cydx.HashAlgoBlake2b_384: common.BLAKE2b_384,
+	cydx.HashAlgoBlake2b_512: common.BLAKE2b_512,
+	cydx.HashAlgoBlake3:      common.BLAKE3,
+}
+
+var cdx_types_to_spdx_purpose = map[cydx.ComponentType]string{
+	cydx.ComponentTypeApplication: "APPLICATION",
+	cydx.ComponentTypeContainer:   "CONTAINER",
+	cydx.ComponentTypeDevice:      "DEVICE",
+	cydx.ComponentTypeFile:        "FILE",
+	cydx.ComponentTypeFramework:   "FRAMEWORK",
+	cydx.ComponentTypeLibrary:     "LIBRARY",
+	cydx.ComponentTypeFirmware:    "FIRMWARE",
+	cydx.ComponentTypeOS:          "OPERATING-SYSTEM",
+}
+
+// toSpdxDocument converts the merged CycloneDX bom into an SPDX 2.3 document.
+//
+// Components become packages, nested components become CONTAINS relationships,
+// dependencies become DEPENDS_ON relationships, purl/cpe become external references
+// and licenses become the concluded license.
+//
+// The conversion is lossy, the following have no SPDX 2.3 equivalent and are dropped:
+// services, vulnerabilities, properties, compositions, annotations, formulation,
+// declarations, pedigree, evidence, non purl/cpe external references and licenses
+// which only carry a name.
+func toSpdxDocument(bom *cydx.BOM) (*v2_3.Document, error) {
+	if bom == nil || bom.Metadata == nil || bom.Metadata.Component == nil {
+		return nil, fmt.Errorf("cyclonedx bom does not have a primary component")
+	}
+
+	pc := bom.Metadata.Component
+
+	doc := v2_3.Document{}
+	doc.SPDXVersion = v2_3.Version
+	doc.DataLicense = v2_3.DataLicense
+	doc.SPDXIdentifier = common.ElementID("DOCUMENT")
+	doc.DocumentName = pc.Name
+	doc.DocumentNamespace = spdxNamespace(pc.Name)
+
+	doc.CreationInfo = &v2_3.CreationInfo{}
+	doc.CreationInfo.Created = bom.Metadata.Timestamp
+	doc.CreationInfo.CreatorComment = fmt.Sprintf("Converted from CycloneDX by sbomasm (%s)", version.GetVersionInfo().GitVersion)
+	doc.CreationInfo.Creators = spdxCreators(bom.Metadata)
+
+	// cdx bom-ref to spdx identifier
+	ids := make(map[string]common.ElementID)
+
+	rootID := common.ElementID(fmt.Sprintf("RootPackage-%s", uuid.New().String()))
+	ids[pc.BOMRef] = rootID
+	doc.Packages = append(doc.Packages, cdxCompToSpdxPkg(pc, rootID))
+
+	doc.Relationships = append(doc.Relationships, &v2_3.Relationship{
+		RefA:         common.MakeDocElementID("", "DOCUMENT"),
+		RefB:         common.MakeDocElementID("", string(rootID)),
+		Relationship: common.TypeRelationshipDescribe,
+	})
+
+	var walk func(parent common.ElementID, comps *[]cydx.Component)
+	walk = func(parent common.ElementID, comps *[]cydx.Component) {
+		for i := range lo.FromPtr(comps) {
+			comp := &(*comps)[i]
+
+			id, seen := ids[comp.BOMRef]
+			if !seen || comp.BOMRef == "" {
+				id = common.ElementID(fmt.Sprintf("Package-%s", uuid.New().String()))
+				if comp.BOMRef != "" {
+					ids[comp.BOMRef] = id
+				}
+				doc.Packages = append(doc.Packages, cdxCompToSpdxPkg(comp, id))
+			}
+
+			if parent != "" {
+				doc.Relationships = append(doc.Relationships, &v2_3.Relationship{
+					RefA:         common.MakeDocElementID("", string(parent)),
+					RefB:         common.MakeDocElementID("", string(id)),
+					Relationship: common.TypeRelationshipContains,
+				})
+			}
+
+			walk(id, comp.Components)
+		}
+	}
+
+	walk(rootID, pc.Components)
+	walk("", bom.Components)
+
+	for _, dep := range lo.FromPtr(bom.Dependencies) {
+		refA, ok := ids[dep.Ref]
+		if !ok {
+			continue
+		}
+
+		for _, d := range lo.FromPtr(dep.Dependencies) {
+			refB, ok := ids[d]
+			if !ok {
+				continue
+			}
+
+			doc.Relationships = append(doc.Relationships, &v2_3.Relationship{
+				RefA:         common.MakeDocElementID("", string(refA)),
+				RefB:         common.MakeDocElementID("", string(refB)),
+				Relationship: common.TypeRelationshipDependsOn,
+			})
+		}
+	}
+
+	doc.Relationships = lo.UniqBy(doc.Relationships, func(r *v2_3.Relationship) string {
+		return fmt.Sprintf("%s-%s->%s", r.RefA.ElementRefID, r.Relationship, r.RefB.ElementRefID)
+	})
+
+	return &doc, nil
+}
+
+func spdxNamespace(docName string) string {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "spdx.org",
+		Path:   fmt.Sprintf("%s/%s-%s", "spdxdocs", docName, uuid.New().String()),
+	}
+	return u.String()
+}
+
+func spdxCreators(md *cydx.Metadata) []common.Creator {
+	creators := []common.Creator{}
+
+	for _, author := range lo.FromPtr(md.Authors) {
+		if author.Name == "" {
+			continue
+		}
+
+		creator := author.Name
+		if author.Email != "" {
+			creator = fmt.Sprintf("%s (%s)", author.Name, author.Email)
+		}
+		creators = append(creators, common.Creator{CreatorType: "Person", Creator: creator})
+	}
+
+	if md.Tools != nil {
+		for _, tool := range lo.FromPtr(md.Tools.Components) {
+			if tool.Name == "" {
+				continue
+			}
+			creators = append(creators, common.Creator{
+				CreatorType: "Tool",
+				Creator:     strings.Join(lo.Compact([]string{tool.Name, tool.Version}), "-"),
+			})
+		}
+	}
+
+	return lo.UniqBy(creators, func(c common.Creator) string {
+		return fmt.Sprintf("%s:%s", c.CreatorType, c.Creator)
+	})
+}
+
+func cdxCompToSpdxPkg(comp *cydx.Component, id common.ElementID) *v2_3.Package {
+	pkg := v2_3.Package{}
+
+	pkg.PackageSPDXIdentifier = id
+	pkg.PackageName = comp.Name
+	pkg.PackageVersion = comp.Version
+	pkg.PackageDescription = comp.Description
+	pkg.PackageDownloadLocation = spdxNOA
+	pkg.FilesAnalyzed = false
+	pkg.PrimaryPackagePurpose = cdx_types_to_spdx_purpose[comp.Type]
+
+	if comp.Supplier != nil && comp.Supplier.Name != "" {
+		pkg.PackageSupplier = &common.Supplier{SupplierType: "Organization", Supplier: comp.Supplier.Name}
+
+		contacts := lo.FromPtr(comp.Supplier.Contact)
+		if len(contacts) > 0 && contacts[0].Email != "" {
+			pkg.PackageSupplier.Supplier = fmt.Sprintf("%s (%s)", comp.Supplier.Name, contacts[0].Email)
+		}
+	}
+
+	for _, h := range lo.FromPtr(comp.Hashes) {
+		algo, ok := cdx_hash_algos_to_spdx[h.Algorithm]
+		if !ok || h.Value == "" {
+			continue
+		}
+		pkg.PackageChecksums = append(pkg.PackageChecksums, common.Checksum{Algorithm: algo, Value: h.Value})
+	}
+
+	pkg.PackageLicenseConcluded = cdxLicensesToSpdxExpression(comp.Licenses)
+	pkg.PackageLicenseDeclared = spdxNOA
+
+	pkg.PackageCopyrightText = spdxNOA
+	if comp.Copyright != "" {
+		pkg.PackageCopyrightText = comp.Copyright
+	}
+
+	if comp.PackageURL != "" {
+		pkg.PackageExternalReferences = append(pkg.PackageExternalReferences, &v2_3.PackageExternalReference{
+			Category: common.CategoryPackageManager,
+			RefType:  common.TypePackageManagerPURL,
+			Locator:  comp.PackageURL,
+		})
+	}
+
+	if comp.CPE != "" {
+		refType := common.TypeSecurityCPE23Type
+		if !strings.HasPrefix(comp.CPE, "cpe:2.3:") {
+			refType = common.TypeSecurityCPE22Type
+		}
+		pkg.PackageExternalReferences = append(pkg.PackageExternalReferences, &v2_3.PackageExternalReference{
+			Category: common.CategorySecurity,
+			RefType:  refType,
+			Locator:  comp.CPE,
+		})
+	}
+
+	return &pkg
+}
+
+func cdxLicensesToSpdxExpression(lics *cydx.Licenses) string {
+	exprs := []string{}
+
+	for _, l := range lo.FromPtr(lics) {
+		if l.Expression != "" {
+			exprs = append(exprs, l.Expression)
+		} else if l.License != nil && l.License.ID != "" {
+			exprs = append(exprs, l.License.ID)
+		}
+	}
+
+	exprs = lo.Uniq(exprs)
+
+	switch len(exprs) {
+	case 0:
+		return spdxNOA
+	case 1:
+		return exprs[0]
+	}
+
+	return strings.Join(lo.Map(exprs, func(e string, _ int) string {
+		if strings.Contains(e, " ") {
+			return fmt.Sprintf("(%s)", e)
+		}
+		return e
+	}), " AND ")
+}
@@ -47,10 +47,7 @@ func (s *uniqueComponentService) StoreAndCloneWithNewID(c *cydx.Component) (*cyd
 		return nil, false
 	}
 
-	lookupKey := fmt.Sprintf("%s-%s-%s",
-		strings.ToLower(string(c.Type)),
-		strings.ToLower(c.Name),
-		strings.ToLower(c.Version))
+	lookupKey := componentLookupKey(c)
 
 	if foundComp, ok := s.compMap[lookupKey]; ok {
 		if c.BOMRef != foundComp.BOMRef {
@@ -72,6 +69,14 @@ func (s *uniqueComponentService) StoreAndCloneWithNewID(c *cydx.Component) (*cyd
 	return nc, false
 }
 
+// componentLookupKey identifies components which are merged into one
+func componentLookupKey(c *cydx.Component) string {
+	return fmt.Sprintf("%s-%s-%s",
+		strings.ToLower(string(c.Type)),
+		strings.ToLower(c.Name),
+		strings.ToLower(c.Version))
+}
+
 func (s *uniqueComponentService) ResolveDepID(depID string) (string, bool) {
 	if newID, ok := s.idMap[depID]; ok {
 		return newID, true
@@ -20,15 +20,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
-	"time"
 
 	cydx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/google/uuid"
-	"github.com/interlynk-io/sbomasm/pkg/detect"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
 	"github.com/samber/lo"
+	"go.uber.org/zap"
 	"sigs.k8s.io/release-utils/version"
 )
 
@@ -140,51 +138,6 @@ func isEmptyValue(v interface{}) bool {
 	return false
 }
 
-func loadBom(ctx context.Context, path string) (*cydx.BOM, error) {
-	log := logger.FromContext(ctx)
-
-	var err error
-	var bom *cydx.BOM
-
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	spec, format, err := detect.Detect(f)
-	if err != nil {
-		return nil, err
-	}
-
-	log.Debugf("loading bom:%s spec:%s format:%s", path, spec, format)
-
-	switch format {
-	case detect.FileFormatJSON:
-		bom = new(cydx.BOM)
-		decoder := cydx.NewBOMDecoder(f, cydx.BOMFileFormatJSON)
-		if err = decoder.Decode(bom); err != nil {
-			return nil, err
-		}
-	case detect.FileFormatXML:
-		bom = new(cydx.BOM)
-		decoder := cydx.NewBOMDecoder(f, cydx.BOMFileFormatXML)
-		if err = decoder.Decode(bom); err != nil {
-			return nil, err
-		}
-	default:
-		panic("unsupported file format") // TODO: return error instead of panic
-	}
-
-	return bom, nil
-}
-
-func utcNowTime() string {
-	location, _ := time.LoadLocation("UTC")
-	locationTime := time.Now().In(location)
-	return locationTime.Format(time.RFC3339)
-}
-
 func buildToolList(in []*cydx.BOM) *cydx.ToolsChoice {
 	tools := cydx.ToolsChoice{}
 
@@ -253,6 +206,270 @@ func buildToolList(in []*cydx.BOM) *cydx.ToolsChoice {
 	return &tools
 }
 
+// buildFormulationList unions the formulas of each sbom by bom-ref, formulas
+// without a bom-ref are always kept. References to components are pointed to
+// the merged component ids. When two sboms carry different formulas under the
+// same bom-ref the first one is kept and a warning is logged.
+func buildFormulationList(ctx context.Context, in []*cydx.BOM, cs *uniqueComponentService) ([]cydx.Formula, error) {
+	log := logger.FromContext(ctx)
+
+	formulas := []cydx.Formula{}
+	seen := make(map[string]int)
+
+	for _, bom := range in {
+		for _, orig := range lo.FromPtr(bom.Formulation) {
+			f, err := cloneFormula(orig)
+			if err != nil {
+				return nil, fmt.Errorf("unable to copy formulation %s: %w", orig.BOMRef, err)
+			}
+			remapFormula(&f, cs)
+
+			if f.BOMRef == "" {
+				formulas = append(formulas, f)
+				continue
+			}
+
+			if i, ok := seen[f.BOMRef]; ok {
+				if !reflect.DeepEqual(formulas[i], f) {
+					log.Warnf("formulation %s differs across input sboms, keeping the first one", f.BOMRef)
+				}
+				continue
+			}
+
+			seen[f.BOMRef] = len(formulas)
+			formulas = append(formulas, f)
+		}
+	}
+
+	return formulas, nil
+}
+
+// cloneFormula deep copies a formula so remapping its references leaves the
+// input sbom untouched.
+func cloneFormula(f cydx.Formula) (cydx.Formula, error) {
+	var nf cydx.Formula
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return nf, err
+	}
+
+	err = json.Unmarshal(b, &nf)
+	return nf, err
+}
+
+// remapFormula points the resource references, inputs, outputs and runtime
+// topologies of the formula workflows and tasks to the merged component ids.
+func remapFormula(f *cydx.Formula, cs *uniqueComponentService) {
+	remapRef := func(r *cydx.ResourceReferenceChoice) {
+		if r == nil {
+			return
+		}
+		if newID, ok := cs.ResolveDepID(r.Ref); ok {
+			r.Ref = newID
+		}
+	}
+
+	remapRefs := func(refs *[]cydx.ResourceReferenceChoice) {
+		for i := range lo.FromPtr(refs) {
+			remapRef(&(*refs)[i])
+		}
+	}
+
+	remapIO := func(inputs *[]cydx.TaskInput, outputs *[]cydx.TaskOutput) {
+		for i := range lo.FromPtr(inputs) {
+			remapRef((*inputs)[i].Resource)
+			remapRef((*inputs)[i].Source)
+			remapRef((*inputs)[i].Target)
+		}
+		for i := range lo.FromPtr(outputs) {
+			remapRef((*outputs)[i].Resource)
+			remapRef((*outputs)[i].Source)
+			remapRef((*outputs)[i].Target)
+		}
+	}
+
+	remapTrigger := func(t *cydx.TaskTrigger) {
+		if t == nil {
+			return
+		}
+		remapRefs(t.ResourceReferences)
+		remapIO(t.Inputs, t.Outputs)
+		if t.Event != nil {
+			remapRef(t.Event.Source)
+			remapRef(t.Event.Target)
+		}
+	}
+
+	remapTopology := func(deps *[]cydx.Dependency) {
+		for i := range lo.FromPtr(deps) {
+			d := &(*deps)[i]
+			if newID, ok := cs.ResolveDepID(d.Ref); ok {
+				d.Ref = newID
+			}
+			if d.Dependencies != nil {
+				ids := lo.Map(*d.Dependencies, func(dep string, _ int) string {
+					if newID, ok := cs.ResolveDepID(dep); ok {
+						return newID
+					}
+					return dep
+				})
+				d.Dependencies = &ids
+			}
+		}
+	}
+
+	for i := range lo.FromPtr(f.Workflows) {
+		w := &(*f.Workflows)[i]
+		remapRefs(w.ResourceReferences)
+		remapTrigger(w.Trigger)
+		remapIO(w.Inputs, w.Outputs)
+		remapTopology(w.RuntimeTopology)
+
+		for j := range lo.FromPtr(w.Tasks) {
+			t := &(*w.Tasks)[j]
+			remapRefs(t.ResourceReferences)
+			remapTrigger(t.Trigger)
+			remapIO(t.Inputs, t.Outputs)
+			remapTopology(t.RuntimeTopology)
+		}
+	}
+}
+
+// buildDeclarations unions the declarations of each sbom, assessors, claims,
+// evidence and targets by bom-ref and attestations by content. Claim targets
+// are pointed to the merged component ids. A single affirmation is kept and
+// the declarations signature is only kept when one sbom carries declarations,
+// as it does not cover the merged declarations.
+func buildDeclarations(ctx context.Context, in []*cydx.BOM, cs *uniqueComponentService) *cydx.Declarations {
+	log := logger.FromContext(ctx)
+
+	decls := lo.FilterMap(in, func(bom *cydx.BOM, _ int) (*cydx.Declarations, bool) {
+		return bom.Declarations, bom.Declarations != nil
+	})
+
+	if len(decls) == 0 {
+		return nil
+	}
+
+	out := cydx.Declarations{}
+
+	assessors := unionByRef(log, "assessor", lo.FlatMap(decls, func(d *cydx.Declarations, _ int) []cydx.Assessor {
+		return lo.FromPtr(d.Assessors)
+	}), func(a cydx.Assessor) string { return string(a.BOMRef) })
+
+	attestations := lo.Reduce(decls, func(list []cydx.Attestation, d *cydx.Declarations, _ int) []cydx.Attestation {
+		for _, a := range lo.FromPtr(d.Attestations) {
+			if !lo.ContainsBy(list, func(b cydx.Attestation) bool { return reflect.DeepEqual(a, b) }) {
+				list = append(list, a)
+			}
+		}
+		return list
+	}, []cydx.Attestation{})
+
+	claims := unionByRef(log, "claim", lo.FlatMap(decls, func(d *cydx.Declarations, _ int) []cydx.Claim {
+		return lo.FromPtr(d.Claims)
+	}), func(c cydx.Claim) string { return c.BOMRef })
+
+	for i := range claims {
+		if newID, ok := cs.ResolveDepID(string(claims[i].Target)); ok {
+			claims[i].Target = cydx.BOMReference(newID)
+		}
+	}
+
+	evidence := unionByRef(log, "evidence", lo.FlatMap(decls, func(d *cydx.Declarations, _ int) []cydx.DeclarationEvidence {
+		return lo.FromPtr(d.Evidence)
+	}), func(e cydx.DeclarationEvidence) string { return e.BOMRef })
+
+	targets := lo.FilterMap(decls, func(d *cydx.Declarations, _ int) (*cydx.Targets, bool) {
+		return d.Targets, d.Targets != nil
+	})
+
+	if len(targets) > 0 {
+		orgs := lo.UniqBy(lo.FlatMap(targets, func(t *cydx.Targets, _ int) []cydx.OrganizationalEntity {
+			return lo.FromPtr(t.Organizations)
+		}), func(o cydx.OrganizationalEntity) string { return fmt.Sprintf("%+v", o) })
+
+		comps := unionByRef(log, "target component", lo.FlatMap(targets, func(t *cydx.Targets, _ int) []cydx.Component {
+			return lo.FromPtr(t.Components)
+		}), func(c cydx.Component) string { return c.BOMRef })
+
+		services := unionByRef(log, "target service", lo.FlatMap(targets, func(t *cydx.Targets, _ int) []cydx.Service {
+			return lo.FromPtr(t.Services)
+		}), func(s cydx.Service) string { return s.BOMRef })
+
+		out.Targets = &cydx.Targets{}
+		if len(orgs) > 0 {
+			out.Targets.Organizations = &orgs
+		}
+		if len(comps) > 0 {
+			out.Targets.Components = &comps
+		}
+		if len(services) > 0 {
+			out.Targets.Services = &services
+		}
+	}
+
+	if len(assessors) > 0 {
+		out.Assessors = &assessors
+	}
+	if len(attestations) > 0 {
+		out.Attestations = &attestations
+	}
+	if len(claims) > 0 {
+		out.Claims = &claims
+	}
+	if len(evidence) > 0 {
+		out.Evidence = &evidence
+	}
+
+	for _, d := range decls {
+		if d.Affirmation == nil {
+			continue
+		}
+		if out.Affirmation == nil {
+			out.Affirmation = d.Affirmation
+		} else if !reflect.DeepEqual(out.Affirmation, d.Affirmation) {
+			log.Warnf("declarations affirmation differs across input sboms, keeping the first one")
+		}
+	}
+
+	if len(decls) == 1 {
+		out.Signature = decls[0].Signature
+	} else if lo.SomeBy(decls, func(d *cydx.Declarations) bool { return d.Signature != nil }) {
+		log.Warnf("declarations signature dropped, it does not cover the declarations merged from %d sboms", len(decls))
+	}
+
+	return &out
+}
+
+// unionByRef keeps the first item for each ref, items without a ref are always
+// kept. A warning is logged when items under the same ref differ.
+func unionByRef[T any](log *zap.SugaredLogger, kind string, items []T, ref func(T) string) []T {
+	list := []T{}
+	seen := make(map[string]int)
+
+	for _, item := range items {
+		r := ref(item)
+		if r == "" {
+			list = append(list, item)
+			continue
+		}
+
+		if i, ok := seen[r]; ok {
+			if !reflect.DeepEqual(list[i], item) {
+				log.Warnf("declarations %s %s differs across input sboms, keeping the first one", kind, r)
+			}
+			continue
+		}
+
+		seen[r] = len(list)
+		list = append(list, item)
+	}
+
+	return list
+}
+
 func buildComponentList(in []*cydx.BOM, cs *uniqueComponentService) []cydx.Component {
 	finalList := []cydx.Component{}
 
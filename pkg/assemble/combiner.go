@@ -18,17 +18,24 @@ package assemble
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/interlynk-io/sbomasm/pkg/assemble/cdx"
 	"github.com/interlynk-io/sbomasm/pkg/assemble/spdx"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
 	"github.com/samber/lo"
 )
 
 type combiner struct {
 	c         *config
 	finalSpec string
+
+	// inputs skipped on error and the number of inputs before skipping
+	skipped []SkippedInput
+	total   int
 }
 
 func newCombiner(c *config) *combiner {
@@ -38,11 +45,24 @@ func newCombiner(c *config) *combiner {
 func (c *combiner) combine() error {
 	log := logger.FromContext(*c.c.ctx)
 
+	if err := c.resolveOutputFile(); err != nil {
+		return err
+	}
+
+	specVersion, err := c.outputSpecVersion()
+	if err != nil {
+		return err
+	}
+
 	if strings.EqualFold(c.finalSpec, "cyclonedx") {
 		log.Debugf("combining %d CycloneDX sboms", len(c.c.input.files))
 		ms := toCDXMergerSettings(c.c)
+		ms.Output.SpecVersion = specVersion
 
 		err := cdx.Merge(ms)
+		for _, s := range ms.Skipped {
+			c.skipped = append(c.skipped, SkippedInput{File: s.File, Reason: s.Reason})
+		}
 		if err != nil {
 			return err
 		}
@@ -51,9 +71,17 @@ func (c *combiner) combine() error {
 	if strings.EqualFold(c.finalSpec, "spdx") {
 		log.Debugf("combining %d SPDX sboms", len(c.c.input.files))
 
+		if c.c.Output.RootFilter != "" {
+			return fmt.Errorf("root filter is only supported for cyclonedx inputs")
+		}
+
 		ms := toSpdxMergerSettings(c.c)
+		ms.Output.SpecVersion = specVersion
 
 		err := spdx.Merge(ms)
+		for _, s := range ms.Skipped {
+			c.skipped = append(c.skipped, SkippedInput{File: s.File, Reason: s.Reason})
+		}
 		if err != nil {
 			return err
 		}
@@ -62,17 +90,97 @@ func (c *combiner) combine() error {
 	return nil
 }
 
+// resolveOutputFile names the output file when the output path is a directory,
+// using the primary component name & version and the output spec & format
+// e.g. myapp-1.2.3.cdx.json
+func (c *combiner) resolveOutputFile() error {
+	path := c.c.Output.file
+	if path == "" {
+		return nil
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		if strings.HasSuffix(path, string(os.PathSeparator)) {
+			return fmt.Errorf("output directory %s does not exist", path)
+		}
+		return nil
+	}
+
+	if !stat.IsDir() {
+		return nil
+	}
+
+	spec := "cdx"
+	if strings.EqualFold(c.finalSpec, "spdx") || strings.EqualFold(c.c.Output.Spec, "spdx") {
+		spec = "spdx"
+	}
+
+	format := c.c.Output.FileFormat
+	if spec == "spdx" || format == "" {
+		format = "json"
+	}
+
+	name := strings.Join(lo.Compact([]string{c.c.App.Name, c.c.App.Version}), "-")
+	name = strings.NewReplacer(" ", "-", "/", "-", string(os.PathSeparator), "-").Replace(name)
+	if name == "" {
+		return fmt.Errorf("output directory %s requires the primary component name to name the file", path)
+	}
+
+	c.c.Output.file = filepath.Join(path, fmt.Sprintf("%s.%s.%s", name, spec, format))
+
+	logger.FromContext(*c.c.ctx).Debugf("output directory %s, writing to %s", path, c.c.Output.file)
+
+	return nil
+}
+
+// outputSpecVersion returns the spec version of the assembled sbom. The
+// default cyclonedx spec version does not apply to spdx output, which
+// rejects an explicit cyclonedx version instead of ignoring it.
+func (c *combiner) outputSpecVersion() (string, error) {
+	if !strings.EqualFold(c.finalSpec, "spdx") && !strings.EqualFold(c.c.Output.Spec, "spdx") {
+		return c.c.Output.SpecVersion, nil
+	}
+
+	if !c.c.Output.specVersionSet {
+		return "", nil
+	}
+
+	if c.c.Output.SpecVersion != "2.3" {
+		return "", fmt.Errorf("unsupported spec version %s for spdx output :: use 2.3 or leave it unset", c.c.Output.SpecVersion)
+	}
+
+	return c.c.Output.SpecVersion, nil
+}
+
 func (c *combiner) canCombine() error {
 	specs := []string{}
 
+	files := []string{}
+	c.total = len(c.c.input.files)
+
 	for _, doc := range c.c.input.files {
-		spec, _, err := detectSbom(doc)
+		// oversized inputs are skipped before detection reads them
+		spec, _, err := sbom.Detect(doc, c.c.input.format, c.c.input.limits)
 		if err != nil {
-			return fmt.Errorf("unable to detect sbom format for %s: %v", doc, err)
+			err = fmt.Errorf("unable to read sbom %s: %v", doc, err)
+			if !c.c.Assemble.continueOnError {
+				return err
+			}
+			logger.FromContext(*c.c.ctx).Warnf("skipping input: %v", err)
+			c.skipped = append(c.skipped, SkippedInput{File: doc, Reason: err.Error()})
+			continue
 		}
-		specs = append(specs, spec)
+		specs = append(specs, string(spec))
+		files = append(files, doc)
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("none of the %d input sboms could be detected", c.total)
 	}
 
+	c.c.input.files = files
+
 	// all input specs should be of the same type
 	if len(lo.Uniq(specs)) != 1 {
 		return fmt.Errorf("input sboms are not of the same type")
@@ -87,6 +195,7 @@ func toCDXMergerSettings(c *config) *cdx.MergeSettings {
 	ms := cdx.MergeSettings{}
 
 	ms.Ctx = c.ctx
+	ms.ContinueOnError = c.Assemble.continueOnError
 
 	ms.Assemble.FlatMerge = c.Assemble.FlatMerge
 	ms.Assemble.HierarchicalMerge = c.Assemble.HierarchicalMerge
@@ -94,9 +203,12 @@ func toCDXMergerSettings(c *config) *cdx.MergeSettings {
 	ms.Assemble.IncludeComponents = c.Assemble.IncludeComponents
 	ms.Assemble.IncludeDuplicateComponents = c.Assemble.includeDuplicateComponents
 	ms.Assemble.IncludeDependencyGraph = c.Assemble.IncludeDependencyGraph
+	ms.Assemble.Plan = c.Assemble.plan
 
 	ms.Input.Files = []string{}
 	ms.Input.Files = append(ms.Input.Files, c.input.files...)
+	ms.Input.Format = c.input.format
+	ms.Input.Limits = c.input.limits
 
 	ms.Output.File = c.Output.file
 	ms.Output.Upload = c.Output.Upload
@@ -104,6 +216,15 @@ func toCDXMergerSettings(c *config) *cdx.MergeSettings {
 	ms.Output.Url = c.Output.Url
 	ms.Output.ApiKey = c.Output.ApiKey
 	ms.Output.FileFormat = c.Output.FileFormat
+	ms.Output.Pretty = c.Output.Pretty
+	ms.Output.Indent = c.Output.Indent
+	ms.Output.SplitByType = c.Output.SplitByType
+	ms.Output.RootFilter = c.Output.RootFilter
+	ms.Output.Annotation = c.Output.Annotation
+	ms.Output.Timestamp = c.Output.Timestamp
+	ms.Output.Properties = lo.Map(c.Output.Properties, func(p property, _ int) cdx.Property {
+		return cdx.Property{Name: p.Name, Value: p.Value}
+	})
 	ms.Output.Spec = c.Output.Spec
 	ms.Output.SpecVersion = c.Output.SpecVersion
 
@@ -146,18 +267,32 @@ func toSpdxMergerSettings(c *config) *spdx.MergeSettings {
 	ms := spdx.MergeSettings{}
 
 	ms.Ctx = c.ctx
+	ms.ContinueOnError = c.Assemble.continueOnError
 
 	ms.Assemble.FlatMerge = c.Assemble.FlatMerge
 	ms.Assemble.HierarchicalMerge = c.Assemble.HierarchicalMerge
+	ms.Assemble.AssemblyMerge = c.Assemble.AssemblyMerge
 	ms.Assemble.IncludeComponents = c.Assemble.IncludeComponents
 	ms.Assemble.IncludeDuplicateComponents = c.Assemble.includeDuplicateComponents
 	ms.Assemble.IncludeDependencyGraph = c.Assemble.IncludeDependencyGraph
+	ms.Assemble.Plan = c.Assemble.plan
+	ms.Assemble.ExternalDocumentRefs = c.Assemble.ExternalDocumentRefs
+	ms.Assemble.StripFiles = c.Assemble.StripFiles
 
 	ms.Input.Files = []string{}
 	ms.Input.Files = append(ms.Input.Files, c.input.files...)
+	ms.Input.Format = c.input.format
+	ms.Input.Limits = c.input.limits
 
 	ms.Output.File = c.Output.file
 	ms.Output.FileFormat = c.Output.FileFormat
+	ms.Output.Pretty = c.Output.Pretty
+	ms.Output.Indent = c.Output.Indent
+	ms.Output.Annotation = c.Output.Annotation
+	ms.Output.Timestamp = c.Output.Timestamp
+	ms.Output.Properties = lo.Map(c.Output.Properties, func(p property, _ int) spdx.Property {
+		return spdx.Property{Name: p.Name, Value: p.Value}
+	})
 
 	ms.App.Name = c.App.Name
 	ms.App.Version = c.App.Version
@@ -28,7 +28,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/interlynk-io/sbomasm/pkg/assemble/cdx"
+	"github.com/interlynk-io/sbomasm/pkg/assemble/spdx"
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/timestamp"
 	"github.com/samber/lo"
 	"gopkg.in/yaml.v2"
 )
@@ -75,19 +79,40 @@ type app struct {
 	Copyright      string     `yaml:"copyright,omitempty"`
 }
 
+type property struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
 type output struct {
 	Spec            string `yaml:"spec"`
 	SpecVersion     string `yaml:"spec_version"`
 	FileFormat      string `yaml:"file_format"`
+	Pretty          bool   `yaml:"pretty"`
+	Indent          string `yaml:"indent,omitempty"`
+	SplitByType     bool   `yaml:"split_by_type,omitempty"`
+	RootFilter      string `yaml:"root_filter,omitempty"`
 	file            string
 	Upload          bool
 	UploadProjectID uuid.UUID
 	Url             string
 	ApiKey          string
+
+	// provenance recorded on the assembled sbom
+	Annotation string     `yaml:"annotation,omitempty"`
+	Properties []property `yaml:"properties,omitempty"`
+
+	// now, keep or an RFC3339 value
+	Timestamp string `yaml:"timestamp,omitempty"`
+
+	// specVersionSet is false when SpecVersion is the cyclonedx default
+	specVersionSet bool
 }
 
 type input struct {
-	files []string
+	files  []string
+	format detect.FileFormat
+	limits limits.Limits
 }
 
 type assemble struct {
@@ -97,6 +122,15 @@ type assemble struct {
 	FlatMerge                  bool `yaml:"flat_merge"`
 	HierarchicalMerge          bool `yaml:"hierarchical_merge"`
 	AssemblyMerge              bool `yaml:"assembly_merge"`
+
+	// spdx only: drop, retain or comment external document references to the merged documents
+	ExternalDocumentRefs string `yaml:"external_document_refs,omitempty"`
+
+	// spdx only: drop files, snippets and their relationships keeping only packages
+	StripFiles bool `yaml:"strip_files,omitempty"`
+
+	plan            bool
+	continueOnError bool
 }
 
 type config struct {
@@ -134,6 +168,7 @@ var defaultConfig = config{
 		Spec:        DEFAULT_OUTPUT_SPEC,
 		SpecVersion: DEFAULT_OUTPUT_SPEC_VERSION,
 		FileFormat:  DEFAULT_OUTPUT_FILE_FORMAT,
+		Pretty:      true,
 	},
 	Assemble: assemble{
 		FlatMerge:                  false,
@@ -162,6 +197,7 @@ func NewConfig() *config {
 			Spec:        DEFAULT_OUTPUT_SPEC,
 			SpecVersion: DEFAULT_OUTPUT_SPEC_VERSION,
 			FileFormat:  DEFAULT_OUTPUT_FILE_FORMAT,
+			Pretty:      true,
 		},
 		Assemble: assemble{
 			FlatMerge:                  false,
@@ -197,10 +233,18 @@ func (c *config) readAndMerge(p *Params) error {
 			return err
 		}
 
+		// the default spec version is restored when the file does not set one
+		c.Output.SpecVersion = ""
 		err = yaml.Unmarshal(yF, &c)
 		if err != nil {
 			return err
 		}
+
+		if c.Output.SpecVersion == "" {
+			c.Output.SpecVersion = DEFAULT_OUTPUT_SPEC_VERSION
+		} else {
+			c.Output.specVersionSet = true
+		}
 	} else {
 
 		c.Assemble.FlatMerge = p.FlatMerge
@@ -209,6 +253,18 @@ func (c *config) readAndMerge(p *Params) error {
 	}
 
 	c.input.files = p.Input
+	format, err := detect.ParseFileFormat(p.InputFormat)
+	if err != nil {
+		return err
+	}
+	c.input.format = format
+	c.input.limits = p.Limits
+	c.Assemble.plan = p.Plan
+
+	if p.StripFiles {
+		c.Assemble.StripFiles = true
+	}
+	c.Assemble.continueOnError = p.ContinueOnError
 	c.Output.file = p.Output
 	c.Output.Upload = p.Upload
 	c.Output.UploadProjectID = p.UploadProjectID
@@ -242,6 +298,23 @@ func (c *config) readAndMerge(p *Params) error {
 
 	if p.OutputSpecVersion != "" {
 		c.Output.SpecVersion = strings.Trim(p.OutputSpecVersion, " ")
+		c.Output.specVersionSet = true
+	}
+
+	if p.Annotation != "" {
+		c.Output.Annotation = p.Annotation
+	}
+
+	if p.Timestamp != "" {
+		c.Output.Timestamp = p.Timestamp
+	}
+
+	for _, kv := range p.Properties {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid property %s, use name=value", kv)
+		}
+		c.Output.Properties = append(c.Output.Properties, property{Name: strings.TrimSpace(name), Value: value})
 	}
 
 	return nil
@@ -296,6 +369,12 @@ func (c *config) validate() error {
 	c.Output.SpecVersion = sanitize(c.Output.SpecVersion)
 	c.Output.FileFormat = sanitize(c.Output.FileFormat)
 
+	ts, err := timestamp.Normalize(sanitize(c.Output.Timestamp))
+	if err != nil {
+		return err
+	}
+	c.Output.Timestamp = ts
+
 	for i := range c.App.Author {
 		c.App.Author[i].Name = sanitize(c.App.Author[i].Name)
 		c.App.Author[i].Email = sanitize(c.App.Author[i].Email)
@@ -329,6 +408,19 @@ func (c *config) validate() error {
 		c.Output.FileFormat = DEFAULT_OUTPUT_FILE_FORMAT
 	}
 
+	if c.Output.Indent != "" && !c.Output.Pretty {
+		return fmt.Errorf("indent is set but pretty is false :: set pretty to true or remove indent")
+	}
+
+	if c.Output.Indent != "" && c.Output.FileFormat == "xml" {
+		return fmt.Errorf("indent is not supported for xml output :: remove indent or use json")
+	}
+
+	c.Assemble.ExternalDocumentRefs = strings.ToLower(sanitize(c.Assemble.ExternalDocumentRefs))
+	if !lo.Contains([]string{"", spdx.ExternalDocumentRefsDrop, spdx.ExternalDocumentRefsRetain, spdx.ExternalDocumentRefsComment}, c.Assemble.ExternalDocumentRefs) {
+		return fmt.Errorf("unsupported external_document_refs %s :: use one of drop, retain, comment", c.Assemble.ExternalDocumentRefs)
+	}
+
 	if c.input.files == nil || len(c.input.files) == 0 {
 		return fmt.Errorf("input files are not set")
 	}
@@ -337,7 +429,7 @@ func (c *config) validate() error {
 		return fmt.Errorf("assembly requires more than one sbom file")
 	}
 
-	err := c.validateInputContent()
+	err = c.validateInputContent()
 	if err != nil {
 		return err
 	}
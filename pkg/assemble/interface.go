@@ -18,15 +18,20 @@ package assemble
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
 )
 
 type Params struct {
-	Ctx        *context.Context
-	Input      []string
-	Output     string
-	ConfigPath string
+	Ctx   *context.Context
+	Input []string
+	// InputFormat forces the file format of the inputs instead of detecting it
+	InputFormat string
+	Output      string
+	ConfigPath  string
 
 	// upload requirement
 	Url             string
@@ -47,10 +52,52 @@ type Params struct {
 
 	OutputSpec        string
 	OutputSpecVersion string
+
+	// Plan prints what each input contributes without merging or writing
+	Plan bool
+
+	// Annotation and Properties (name=value) record provenance on the output
+	Annotation string
+	Properties []string
+
+	// Timestamp of the output, now, keep or an RFC3339 value
+	Timestamp string
+
+	// StripFiles drops spdx files keeping only packages
+	StripFiles bool
+
+	Limits limits.Limits
+
+	// ContinueOnError skips inputs which fail to load, Assemble then returns
+	// a PartialMergeError listing them.
+	ContinueOnError bool
+}
+
+type SkippedInput struct {
+	File   string
+	Reason string
+}
+
+// PartialMergeError is returned when the sbom was assembled without the
+// inputs which were skipped on error.
+type PartialMergeError struct {
+	Total   int
+	Skipped []SkippedInput
+}
+
+func (e *PartialMergeError) Error() string {
+	lines := []string{fmt.Sprintf("assembled %d of %d input sboms, skipped:", e.Total-len(e.Skipped), e.Total)}
+	for _, s := range e.Skipped {
+		// the reason names the file
+		lines = append(lines, "  "+s.Reason)
+	}
+	return strings.Join(lines, "\n")
 }
 
 func NewParams() *Params {
-	return &Params{}
+	return &Params{
+		Limits: limits.Default(),
+	}
 }
 
 func Assemble(config *config) error {
@@ -70,5 +117,9 @@ func Assemble(config *config) error {
 	if err != nil {
 		return err
 	}
+
+	if len(cb.skipped) > 0 {
+		return &PartialMergeError{Total: cb.total, Skipped: cb.skipped}
+	}
 	return nil
 }
@@ -19,7 +19,10 @@ package spdx
 import (
 	"context"
 	"errors"
+	"os"
 
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
 	"github.com/spdx/tools-golang/spdx"
 )
 
@@ -53,6 +56,14 @@ var spdx_strings_to_types = map[string]string{
 	"other":            "OTHER",
 }
 
+// Handling of external document references which point to a document
+// that is part of the merge set.
+const (
+	ExternalDocumentRefsDrop    = "drop"
+	ExternalDocumentRefsRetain  = "retain"
+	ExternalDocumentRefsComment = "comment"
+)
+
 type Author struct {
 	Name  string
 	Email string
@@ -74,6 +85,16 @@ type Checksum struct {
 	Value     string
 }
 
+type Property struct {
+	Name  string
+	Value string
+}
+
+type SkippedInput struct {
+	File   string
+	Reason string
+}
+
 type app struct {
 	Name           string
 	Version        string
@@ -90,13 +111,20 @@ type app struct {
 
 type output struct {
 	FileFormat  string
+	Pretty      bool
+	Indent      string
 	Spec        string
 	SpecVersion string
 	File        string
+	Annotation  string
+	Properties  []Property
+	Timestamp   string
 }
 
 type input struct {
-	Files []string
+	Files  []string
+	Format detect.FileFormat
+	Limits limits.Limits
 }
 
 type assemble struct {
@@ -106,6 +134,13 @@ type assemble struct {
 	FlatMerge                  bool
 	HierarchicalMerge          bool
 	AssemblyMerge              bool
+	ExternalDocumentRefs       string
+
+	// StripFiles drops files, snippets and their relationships
+	StripFiles bool
+
+	// Plan reports the contribution of each input instead of merging
+	Plan bool
 }
 
 type MergeSettings struct {
@@ -114,6 +149,11 @@ type MergeSettings struct {
 	Output   output
 	Input    input
 	Assemble assemble
+
+	// ContinueOnError skips the inputs which fail to load instead of
+	// failing the merge, the skipped inputs are listed in Skipped.
+	ContinueOnError bool
+	Skipped         []SkippedInput
 }
 
 func Merge(ms *MergeSettings) error {
@@ -123,10 +163,17 @@ func Merge(ms *MergeSettings) error {
 	}
 
 	if len(ms.Output.SpecVersion) > 0 && !validSpecVersion(ms.Output.SpecVersion) {
-		return errors.New("invalid CycloneDX spec version")
+		return errors.New("invalid SPDX spec version")
 	}
 
 	merger := newMerge(ms)
-	merger.loadBoms()
+	if err := merger.loadBoms(); err != nil {
+		return err
+	}
+
+	if ms.Assemble.Plan {
+		return merger.plan(os.Stdout)
+	}
+
 	return merger.combinedMerge()
 }
@@ -17,8 +17,13 @@
 package spdx
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/google/uuid"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/samber/lo"
 	"github.com/spdx/tools-golang/spdx"
 	"github.com/spdx/tools-golang/spdx/v2/common"
 )
@@ -39,14 +44,37 @@ func newMerge(ms *MergeSettings) *merge {
 	}
 }
 
-func (m *merge) loadBoms() {
+func (m *merge) loadBoms() error {
+	log := logger.FromContext(*m.settings.Ctx)
+	loaded := []string{}
+
 	for _, path := range m.settings.Input.Files {
-		bom, err := loadBom(*m.settings.Ctx, path)
+		bom, err := sbom.LoadSpdx(*m.settings.Ctx, path, m.settings.Input.Format, m.settings.Input.Limits)
+		if err != nil {
+			err = fmt.Errorf("unable to load sbom %s: %w", path, err)
+		}
+
 		if err != nil {
-			panic(err) // TODO: return error instead of panic
+			if !m.settings.ContinueOnError {
+				return err
+			}
+			log.Warnf("skipping input: %v", err)
+			m.settings.Skipped = append(m.settings.Skipped, SkippedInput{File: path, Reason: err.Error()})
+			continue
 		}
+
 		m.in = append(m.in, bom)
+		loaded = append(loaded, path)
+	}
+
+	if len(m.in) == 0 {
+		return fmt.Errorf("none of the %d input sboms could be loaded", len(m.settings.Input.Files))
 	}
+
+	// later steps refer to the inputs by index
+	m.settings.Input.Files = loaded
+
+	return nil
 }
 
 func (m *merge) combinedMerge() error {
@@ -68,10 +96,21 @@ func (m *merge) combinedMerge() error {
 
 	log.Debugf("generated creation with %d creators, created_at %s and license version %s", len(ci.Creators), ci.Created, ci.LicenseListVersion)
 
-	doc.ExternalDocumentReferences = append(doc.ExternalDocumentReferences, externalDocumentRefs(m.in)...)
+	extRefs, mergedRefs, docRefMapper := externalDocumentRefs(m.in, m.settings.Assemble.ExternalDocumentRefs)
+	doc.ExternalDocumentReferences = append(doc.ExternalDocumentReferences, extRefs...)
 
 	log.Debugf("added %d external document references", len(doc.ExternalDocumentReferences))
 
+	if m.settings.Assemble.ExternalDocumentRefs == ExternalDocumentRefsComment && len(mergedRefs) > 0 {
+		ci.CreatorComment = strings.Join(lo.Compact([]string{strings.TrimRight(ci.CreatorComment, "\n"), mergedDocumentRefsComment(mergedRefs)}), "\n")
+		log.Debugf("added %d merged external document references to the creator comment", len(mergedRefs))
+	}
+
+	if m.settings.Assemble.StripFiles {
+		files, rels := stripFiles(m)
+		log.Debugf("stripped %d files and snippets with %d relationships", files, rels)
+	}
+
 	primaryPkg, err := genPrimaryPackage(m)
 	if err != nil {
 		return err
@@ -89,13 +128,20 @@ func (m *merge) combinedMerge() error {
 		return err
 	}
 
-	rels, err := genRelationships(m, pkgMapper, fileMapper)
+	snippets, snippetMapper, err := genSnippetList(m, fileMapper)
+	if err != nil {
+		return err
+	}
+
+	rels, err := genRelationships(m, pkgMapper, fileMapper, snippetMapper, docRefMapper)
 	if err != nil {
 		return err
 	}
 
 	otherLicenses := genOtherLicenses(m.in)
 
+	annotations := genAnnotations(m, pkgs, files, pkgMapper, fileMapper)
+
 	describedPkgs := getDescribedPkgs(m)
 
 	// Add Packages to document
@@ -105,9 +151,20 @@ func (m *merge) combinedMerge() error {
 	// Add Files to document
 	doc.Files = append(doc.Files, files...)
 
+	// Add Snippets to document
+	doc.Snippets = append(doc.Snippets, snippets...)
+
 	// Add OtherLicenses to document
 	doc.OtherLicenses = append(doc.OtherLicenses, otherLicenses...)
 
+	// Add document level Annotations to document
+	doc.Annotations = append(doc.Annotations, annotations...)
+
+	if m.settings.Output.Annotation != "" || len(m.settings.Output.Properties) > 0 {
+		addProvenance(m, doc)
+		log.Debugf("added provenance annotation and %d properties", len(m.settings.Output.Properties))
+	}
+
 	topLevelRels := []*spdx.Relationship{}
 
 	// always add describes relationship between document and primary package
@@ -130,7 +187,15 @@ func (m *merge) combinedMerge() error {
 		// Default to hierarchical merge
 		// Add relationships between primary package and described packages from merge sets
 		for _, dp := range describedPkgs {
-			currentPkgId := pkgMapper[dp]
+			// documents may describe files as well as packages
+			currentPkgId, ok := pkgMapper[dp]
+			if !ok {
+				currentPkgId, ok = fileMapper[dp]
+			}
+			if !ok {
+				log.Warnf("described element %s is not in the merge set, it is not linked to the primary package", dp)
+				continue
+			}
 			topLevelRels = append(topLevelRels, &spdx.Relationship{
 				RefA:                common.MakeDocElementID("", string(primaryPkg.PackageSPDXIdentifier)),
 				RefB:                common.MakeDocElementID("", currentPkgId),
@@ -0,0 +1,101 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spdx
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spdx/tools-golang/spdx/v2/common"
+	"github.com/spdx/tools-golang/spdx/v2/v2_3"
+)
+
+type planSeen struct {
+	input string
+	purl  string
+}
+
+// plan reports how many packages each input document contributes to the
+// merged document, how many match packages of an earlier document and which
+// matches disagree on the purl. Nothing is merged or written.
+func (m *merge) plan(w io.Writer) error {
+	seen := make(map[string]planSeen)
+	var total, added, matched, conflicts int
+
+	fmt.Fprintf(w, "Assembly plan for %d spdx sboms (%s)\n", len(m.in), m.mergeMode())
+
+	for i, doc := range m.in {
+		input := filepath.Base(m.settings.Input.Files[i])
+
+		var inAdded, inMatched int
+		var inConflicts []string
+
+		for _, pkg := range doc.Packages {
+			key := packageLookupKey(pkg)
+			purl := packagePurl(pkg)
+
+			found, ok := seen[key]
+			if !ok {
+				seen[key] = planSeen{input: input, purl: purl}
+				inAdded++
+				continue
+			}
+
+			inMatched++
+			if found.purl != "" && purl != "" && found.purl != purl {
+				inConflicts = append(inConflicts, fmt.Sprintf("%s@%s purl %s differs from %s in %s",
+					pkg.PackageName, pkg.PackageVersion, purl, found.purl, found.input))
+			}
+		}
+
+		fmt.Fprintf(w, "  %s: %d packages, %d new, %d matched, %d conflicts\n",
+			input, len(doc.Packages), inAdded, inMatched, len(inConflicts))
+		for _, c := range inConflicts {
+			fmt.Fprintf(w, "    conflict: %s\n", c)
+		}
+
+		total += len(doc.Packages)
+		added += inAdded
+		matched += inMatched
+		conflicts += len(inConflicts)
+	}
+
+	fmt.Fprintf(w, "Total: %d packages read, %d in the merged sbom, %d matched, %d conflicts\n",
+		total, added, matched, conflicts)
+
+	return nil
+}
+
+func (m *merge) mergeMode() string {
+	switch {
+	case m.settings.Assemble.FlatMerge:
+		return "flat merge"
+	case m.settings.Assemble.AssemblyMerge:
+		return "assembly merge"
+	}
+	return "hierarchical merge"
+}
+
+func packagePurl(pkg *v2_3.Package) string {
+	for _, ref := range pkg.PackageExternalReferences {
+		if ref.RefType == common.TypePackageManagerPURL {
+			return ref.Locator
+		}
+	}
+	return ""
+}
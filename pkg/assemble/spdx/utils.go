@@ -17,7 +17,6 @@
 package spdx
 
 import (
-	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
@@ -27,21 +26,17 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/google/uuid"
-	"github.com/interlynk-io/sbomasm/pkg/detect"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/interlynk-io/sbomasm/pkg/timestamp"
 	"github.com/mitchellh/copystructure"
 	"github.com/pingcap/log"
 	"github.com/samber/lo"
-	spdx_json "github.com/spdx/tools-golang/json"
-	spdx_rdf "github.com/spdx/tools-golang/rdf"
 	"github.com/spdx/tools-golang/spdx"
 	"github.com/spdx/tools-golang/spdx/v2/common"
 	"github.com/spdx/tools-golang/spdx/v2/v2_3"
-	spdx_tv "github.com/spdx/tools-golang/tagvalue"
-	spdx_yaml "github.com/spdx/tools-golang/yaml"
 	"sigs.k8s.io/release-utils/version"
 )
 
@@ -56,50 +51,6 @@ func validSpecVersion(specVersion string) bool {
 	return ok
 }
 
-func loadBom(ctx context.Context, path string) (*v2_3.Document, error) {
-	log := logger.FromContext(ctx)
-
-	var d *v2_3.Document
-	var err error
-
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	spec, format, err := detect.Detect(f)
-	if err != nil {
-		return nil, err
-	}
-
-	log.Debugf("loading bom:%s spec:%s format:%s", path, spec, format)
-
-	switch format {
-	case detect.FileFormatJSON:
-		d, err = spdx_json.Read(f)
-	case detect.FileFormatTagValue:
-		d, err = spdx_tv.Read(f)
-	case detect.FileFormatYAML:
-		d, err = spdx_yaml.Read(f)
-	case detect.FileFormatRDF:
-		d, err = spdx_rdf.Read(f)
-	default:
-		panic("unsupported spdx format")
-
-	}
-
-	if err != nil {
-		return nil, err
-	}
-
-	return d, nil
-}
-
-func utcNowTime() string {
-	return time.Now().UTC().Format(time.RFC3339)
-}
-
 func clonePkg(c *spdx.Package) (*spdx.Package, error) {
 	compCopy, err := copystructure.Copy(c)
 	if err != nil {
@@ -118,6 +69,15 @@ func cloneFile(c *spdx.File) (*spdx.File, error) {
 	return fileCopy.(*spdx.File), nil
 }
 
+func cloneSnippet(c *spdx.Snippet) (*spdx.Snippet, error) {
+	snipCopy, err := copystructure.Copy(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return snipCopy.(*spdx.Snippet), nil
+}
+
 func cloneRelationship(c *spdx.Relationship) (*spdx.Relationship, error) {
 	relCopy, err := copystructure.Copy(c)
 	if err != nil {
@@ -140,22 +100,77 @@ func composeNamespace(docName string) string {
 
 // If we are merging documents, which are included as external references, we should
 // remove those. As they are no longer external references.
-func externalDocumentRefs(docs []*v2_3.Document) []v2_3.ExternalDocumentRef {
+// externalDocumentRefs returns the external document references of all documents,
+// along with the ones pointing to a document in the merge set. Those are only part
+// of the returned refs when they are retained. A DocumentRef id used by several
+// documents for different uris is renamed, the returned mapper holds the new ids
+// keyed by the namespace of the document and the old id.
+func externalDocumentRefs(docs []*v2_3.Document, mode string) ([]v2_3.ExternalDocumentRef, []v2_3.ExternalDocumentRef, map[string]string) {
 	currentDocNamespaces := lo.Map(docs, func(doc *v2_3.Document, _ int) string {
 		return doc.DocumentNamespace
 	})
 
 	refs := []v2_3.ExternalDocumentRef{}
+	merged := []v2_3.ExternalDocumentRef{}
+	mapper := make(map[string]string)
+	uris := make(map[string]string)
+
+	add := func(doc *v2_3.Document, ref v2_3.ExternalDocumentRef) {
+		id := strings.TrimPrefix(ref.DocumentRefID, "DocumentRef-")
+		uri, ok := uris[id]
+		if ok && uri == ref.URI {
+			return
+		}
+
+		if ok {
+			newID := id
+			for i := 2; ; i++ {
+				newID = fmt.Sprintf("%s-%d", id, i)
+				if _, taken := uris[newID]; !taken {
+					break
+				}
+			}
+			log.Debug(fmt.Sprintf("renamed external document reference %s of %s to %s", id, doc.DocumentNamespace, newID))
+			mapper[createLookupKey(doc.DocumentNamespace, id)] = newID
+			ref.DocumentRefID = "DocumentRef-" + newID
+			id = newID
+		}
+
+		uris[id] = ref.URI
+		refs = append(refs, ref)
+	}
 
 	for _, doc := range docs {
 		for _, ref := range doc.ExternalDocumentReferences {
 			if !lo.Contains(currentDocNamespaces, ref.URI) {
-				refs = append(refs, ref)
+				add(doc, ref)
+				continue
+			}
+
+			merged = append(merged, ref)
+			if mode == ExternalDocumentRefsRetain {
+				add(doc, ref)
 			}
 		}
 	}
 
-	return refs
+	uniqRef := func(ref v2_3.ExternalDocumentRef) string {
+		return fmt.Sprintf("%s-%s", ref.DocumentRefID, ref.URI)
+	}
+
+	return refs, lo.UniqBy(merged, uniqRef), mapper
+}
+
+func mergedDocumentRefsComment(refs []v2_3.ExternalDocumentRef) string {
+	lines := lo.Map(refs, func(ref v2_3.ExternalDocumentRef, _ int) string {
+		id := ref.DocumentRefID
+		if !strings.HasPrefix(id, "DocumentRef-") {
+			id = "DocumentRef-" + id
+		}
+		return fmt.Sprintf("%s %s", id, ref.URI)
+	})
+
+	return fmt.Sprintf("External document references to merged documents: %s", strings.Join(lines, ", "))
 }
 
 func getAllCreators(docs []*v2_3.Document, authors []Author) []common.Creator {
@@ -292,7 +307,7 @@ func genCreationInfo(ms *merge) (*v2_3.CreationInfo, error) {
 	ci := v2_3.CreationInfo{}
 
 	// set UTC time
-	ci.Created = utcNowTime()
+	ci.Created = outputTimestamp(ms)
 	ci.CreatorComment = getCreatorComments(ms.in)
 	lVersions := getLicenseListVersion(ms.in)
 	if lVersions != "" {
@@ -303,6 +318,23 @@ func genCreationInfo(ms *merge) (*v2_3.CreationInfo, error) {
 	return &ci, nil
 }
 
+// outputTimestamp resolves the timestamp setting, now by default, keep for the
+// latest creation time of the input documents or a fixed RFC3339 value.
+func outputTimestamp(ms *merge) string {
+	ts := lo.FilterMap(ms.in, func(doc *v2_3.Document, _ int) (string, bool) {
+		if doc.CreationInfo == nil {
+			return "", false
+		}
+		return doc.CreationInfo.Created, doc.CreationInfo.Created != ""
+	})
+
+	resolved, ok := timestamp.Resolve(ms.settings.Output.Timestamp, ts)
+	if !ok {
+		logger.FromContext(*ms.settings.Ctx).Warnf("input sboms have no valid creation time to keep, using the current time")
+	}
+	return resolved
+}
+
 func genPrimaryPackage(ms *merge) (*v2_3.Package, error) {
 	pkg := v2_3.Package{}
 
@@ -383,6 +415,11 @@ func createLookupKey(docName, spdxId string) string {
 	return fmt.Sprintf("%s:%s", docName, spdxId)
 }
 
+// packageLookupKey identifies packages which are merged into one
+func packageLookupKey(pkg *v2_3.Package) string {
+	return fmt.Sprintf("%s-%s", strings.ToLower(pkg.PackageName), strings.ToLower(pkg.PackageVersion))
+}
+
 func genPackageList(ms *merge) ([]*v2_3.Package, map[string]string, error) {
 	var pkgs []*v2_3.Package
 	mapper := make(map[string]string)
@@ -390,7 +427,7 @@ func genPackageList(ms *merge) ([]*v2_3.Package, map[string]string, error) {
 
 	for _, doc := range ms.in {
 		for _, pkg := range doc.Packages {
-			key := fmt.Sprintf("%s-%s", strings.ToLower(pkg.PackageName), strings.ToLower(pkg.PackageVersion))
+			key := packageLookupKey(pkg)
 
 			// if already seen, map the old SPDXID to the new SPDXID
 			if newID, exists := seen[key]; exists {
@@ -473,7 +510,99 @@ func genFileList(ms *merge) ([]*v2_3.File, map[string]string, error) {
 	return files, mapper, nil
 }
 
-func genRelationships(ms *merge, pkgMapper map[string]string, fileMapper map[string]string) ([]*v2_3.Relationship, error) {
+// stripFiles drops the files and snippets of the input documents along with the
+// relationships and annotations referring to them, leaving a package level
+// inventory. Packages are marked as not analyzed since their files are gone.
+func stripFiles(ms *merge) (int, int) {
+	stripped := make(map[string]bool)
+
+	for _, doc := range ms.in {
+		for _, file := range doc.Files {
+			stripped[createLookupKey(doc.DocumentNamespace, string(file.FileSPDXIdentifier))] = true
+		}
+
+		for _, pkg := range doc.Packages {
+			for _, file := range pkg.Files {
+				stripped[createLookupKey(doc.DocumentNamespace, string(file.FileSPDXIdentifier))] = true
+			}
+			pkg.Files = nil
+			pkg.FilesAnalyzed = false
+			pkg.PackageVerificationCode = nil
+		}
+
+		for _, snippet := range doc.Snippets {
+			stripped[createLookupKey(doc.DocumentNamespace, string(snippet.SnippetSPDXIdentifier))] = true
+		}
+
+		doc.Files = nil
+		doc.Snippets = nil
+	}
+
+	rels := 0
+	for _, doc := range ms.in {
+		isStripped := func(ref common.DocElementID) bool {
+			namespace := doc.DocumentNamespace
+			if ref.DocumentRefID != "" {
+				namespace = getDocumentNamespace(ref.DocumentRefID, ms)
+			}
+			return stripped[createLookupKey(namespace, string(ref.ElementRefID))]
+		}
+
+		kept := lo.Reject(doc.Relationships, func(rel *v2_3.Relationship, _ int) bool {
+			return isStripped(rel.RefA) || isStripped(rel.RefB)
+		})
+		rels += len(doc.Relationships) - len(kept)
+		doc.Relationships = kept
+
+		doc.Annotations = lo.Reject(doc.Annotations, func(a *v2_3.Annotation, _ int) bool {
+			return a != nil && isStripped(a.AnnotationSPDXIdentifier)
+		})
+	}
+
+	return len(stripped), rels
+}
+
+// genSnippetList carries the snippets over with new ids, pointing them and their
+// ranges to the merged files. Snippets whose file is not in the merge set are
+// dropped.
+func genSnippetList(ms *merge, fileMapper map[string]string) ([]v2_3.Snippet, map[string]string, error) {
+	var snippets []v2_3.Snippet
+	mapper := make(map[string]string)
+
+	for _, doc := range ms.in {
+		for _, snip := range doc.Snippets {
+			fileKey := createLookupKey(doc.DocumentNamespace, string(snip.SnippetFromFileSPDXIdentifier))
+			newFileID, ok := fileMapper[fileKey]
+			if !ok {
+				log.Warn(fmt.Sprintf("Snippet: Could not find file %s of snippet %s in the merge set", fileKey, snip.SnippetSPDXIdentifier))
+				continue
+			}
+
+			clone, err := cloneSnippet(&snip)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			newSpdxId := common.ElementID(fmt.Sprintf("Snippet-%s", uuid.New().String()))
+			oldSpdxId := createLookupKey(doc.DocumentNamespace, string(snip.SnippetSPDXIdentifier))
+
+			mapper[oldSpdxId] = string(newSpdxId)
+			clone.SnippetSPDXIdentifier = newSpdxId
+			clone.SnippetFromFileSPDXIdentifier = common.ElementID(newFileID)
+
+			for i := range clone.Ranges {
+				clone.Ranges[i].StartPointer.FileSPDXIdentifier = common.ElementID(newFileID)
+				clone.Ranges[i].EndPointer.FileSPDXIdentifier = common.ElementID(newFileID)
+			}
+
+			snippets = append(snippets, *clone)
+		}
+	}
+
+	return snippets, mapper, nil
+}
+
+func genRelationships(ms *merge, pkgMapper map[string]string, fileMapper map[string]string, snippetMapper map[string]string, docRefMapper map[string]string) ([]*v2_3.Relationship, error) {
 	var relationships []*v2_3.Relationship
 
 	docNames := lo.Map(ms.in, func(doc *v2_3.Document, _ int) string {
@@ -499,6 +628,9 @@ func genRelationships(ms *merge, pkgMapper map[string]string, fileMapper map[str
 					clone.RefA.DocumentRefID = ""
 				} else {
 					log.Warn(fmt.Sprintf("RefA: Could not find document name %s in the merge set", rel.RefA.DocumentRefID))
+					if newID, ok := docRefMapper[createLookupKey(doc.DocumentNamespace, rel.RefA.DocumentRefID)]; ok {
+						clone.RefA.DocumentRefID = newID
+					}
 				}
 			}
 
@@ -507,6 +639,9 @@ func genRelationships(ms *merge, pkgMapper map[string]string, fileMapper map[str
 					clone.RefB.DocumentRefID = ""
 				} else {
 					log.Warn(fmt.Sprintf("RefB: Could not find document name %s in the merge set", rel.RefB.DocumentRefID))
+					if newID, ok := docRefMapper[createLookupKey(doc.DocumentNamespace, rel.RefB.DocumentRefID)]; ok {
+						clone.RefB.DocumentRefID = newID
+					}
 				}
 			}
 
@@ -521,6 +656,8 @@ func genRelationships(ms *merge, pkgMapper map[string]string, fileMapper map[str
 					clone.RefA.ElementRefID = common.ElementID(newID)
 				} else if newID, ok := fileMapper[key]; ok {
 					clone.RefA.ElementRefID = common.ElementID(newID)
+				} else if newID, ok := snippetMapper[key]; ok {
+					clone.RefA.ElementRefID = common.ElementID(newID)
 				} else {
 					log.Warn(fmt.Sprintf("RefA: Could not find element %s in the merge set", key))
 				}
@@ -537,6 +674,8 @@ func genRelationships(ms *merge, pkgMapper map[string]string, fileMapper map[str
 					clone.RefB.ElementRefID = common.ElementID(newID)
 				} else if newID, ok := fileMapper[key]; ok {
 					clone.RefB.ElementRefID = common.ElementID(newID)
+				} else if newID, ok := snippetMapper[key]; ok {
+					clone.RefB.ElementRefID = common.ElementID(newID)
 				} else {
 					log.Warn(fmt.Sprintf("RefB: Could not find element %s in the merge set", key))
 				}
@@ -550,17 +689,157 @@ func genRelationships(ms *merge, pkgMapper map[string]string, fileMapper map[str
 	return relationships, nil
 }
 
+func annotationKey(target string, a v2_3.Annotation) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%s", target, a.Annotator.AnnotatorType, a.Annotator.Annotator, a.AnnotationDate, a.AnnotationComment)
+}
+
+// genAnnotations carries annotations from the merge set over to the merged packages,
+// files and document. Annotations on packages which were de-duplicated are folded into
+// the surviving package. Document level annotations which reference a package or file
+// are attached to the remapped element. Annotations are unique by (annotator, date, comment).
+func genAnnotations(ms *merge, pkgs []*v2_3.Package, files []*v2_3.File, pkgMapper map[string]string, fileMapper map[string]string) []*v2_3.Annotation {
+	var docAnnotations []*v2_3.Annotation
+
+	pkgIndex := make(map[string]*v2_3.Package)
+	for _, pkg := range pkgs {
+		pkgIndex[string(pkg.PackageSPDXIdentifier)] = pkg
+	}
+
+	fileIndex := make(map[string]*v2_3.File)
+	for _, file := range files {
+		fileIndex[string(file.FileSPDXIdentifier)] = file
+	}
+
+	seen := make(map[string]bool)
+	for id, pkg := range pkgIndex {
+		for _, a := range pkg.Annotations {
+			seen[annotationKey(id, a)] = true
+		}
+	}
+	for id, file := range fileIndex {
+		for _, a := range file.Annotations {
+			seen[annotationKey(id, a)] = true
+		}
+	}
+
+	addToPkg := func(id string, a v2_3.Annotation) {
+		pkg, ok := pkgIndex[id]
+		if !ok || seen[annotationKey(id, a)] {
+			return
+		}
+		a.AnnotationSPDXIdentifier = common.MakeDocElementID("", id)
+		pkg.Annotations = append(pkg.Annotations, a)
+		seen[annotationKey(id, a)] = true
+	}
+
+	addToFile := func(id string, a v2_3.Annotation) {
+		file, ok := fileIndex[id]
+		if !ok || seen[annotationKey(id, a)] {
+			return
+		}
+		a.AnnotationSPDXIdentifier = common.MakeDocElementID("", id)
+		file.Annotations = append(file.Annotations, a)
+		seen[annotationKey(id, a)] = true
+	}
+
+	for _, doc := range ms.in {
+		// packages which were de-duplicated would lose their annotations
+		for _, pkg := range doc.Packages {
+			key := createLookupKey(doc.DocumentNamespace, string(pkg.PackageSPDXIdentifier))
+			if newID, ok := pkgMapper[key]; ok {
+				for _, a := range pkg.Annotations {
+					addToPkg(newID, a)
+				}
+			}
+		}
+
+		for _, ann := range doc.Annotations {
+			if ann == nil {
+				continue
+			}
+
+			a := *ann
+			ref := a.AnnotationSPDXIdentifier
+
+			if ref.ElementRefID != "" && ref.ElementRefID != doc.SPDXIdentifier && ref.ElementRefID != "DOCUMENT" {
+				namespace := doc.DocumentNamespace
+				if ref.DocumentRefID != "" {
+					namespace = getDocumentNamespace(ref.DocumentRefID, ms)
+				}
+
+				key := createLookupKey(namespace, string(ref.ElementRefID))
+				if newID, ok := pkgMapper[key]; ok {
+					addToPkg(newID, a)
+				} else if newID, ok := fileMapper[key]; ok {
+					addToFile(newID, a)
+				} else {
+					log.Warn(fmt.Sprintf("Annotation: Could not find element %s in the merge set", key))
+				}
+				continue
+			}
+
+			if seen[annotationKey("DOCUMENT", a)] {
+				continue
+			}
+			a.AnnotationSPDXIdentifier = common.MakeDocElementID("", "DOCUMENT")
+			docAnnotations = append(docAnnotations, &a)
+			seen[annotationKey("DOCUMENT", a)] = true
+		}
+	}
+
+	return docAnnotations
+}
+
+// addProvenance records how the document was assembled, the annotation as a
+// document annotation made by sbomasm and the properties, which spdx has no
+// field for, as name: value lines of the creator comment.
+func addProvenance(ms *merge, doc *v2_3.Document) {
+	if ms.settings.Output.Annotation != "" {
+		doc.Annotations = append(doc.Annotations, &v2_3.Annotation{
+			Annotator: common.Annotator{
+				AnnotatorType: "Tool",
+				Annotator:     fmt.Sprintf("%s-%s", "sbomasm", version.GetVersionInfo().GitVersion),
+			},
+			AnnotationDate:           doc.CreationInfo.Created,
+			AnnotationType:           "OTHER",
+			AnnotationSPDXIdentifier: common.MakeDocElementID("", "DOCUMENT"),
+			AnnotationComment:        ms.settings.Output.Annotation,
+		})
+	}
+
+	if len(ms.settings.Output.Properties) > 0 {
+		lines := lo.Map(ms.settings.Output.Properties, func(p Property, _ int) string {
+			return fmt.Sprintf("%s: %s", p.Name, p.Value)
+		})
+		doc.CreationInfo.CreatorComment = strings.Join(lo.Compact(append([]string{strings.TrimRight(doc.CreationInfo.CreatorComment, "\n")}, lines...)), "\n")
+	}
+}
+
 func getDescribedPkgs(ms *merge) []string {
+	log := logger.FromContext(*ms.settings.Ctx)
 	pkgs := []string{}
 
 	for _, doc := range ms.in {
+		described := 0
 		for _, rel := range doc.Relationships {
 			if rel.Relationship == common.TypeRelationshipDescribe {
 				if rel.RefB.ElementRefID != "" {
 					pkgs = append(pkgs, createLookupKey(doc.DocumentNamespace, string(rel.RefB.ElementRefID)))
+					described++
 				}
 			}
 		}
+
+		if described > 0 {
+			continue
+		}
+
+		if pkg := sbom.FallbackPrimaryPackage(doc); pkg != nil {
+			log.Debugf("document %s has no DESCRIBES relationship, using package %s", doc.DocumentName, pkg.PackageSPDXIdentifier)
+			pkgs = append(pkgs, createLookupKey(doc.DocumentNamespace, string(pkg.PackageSPDXIdentifier)))
+		} else {
+			log.Warnf("document %s has no DESCRIBES relationship and no package could be picked as primary", doc.DocumentName)
+		}
 	}
 
 	return pkgs
@@ -582,7 +861,18 @@ func writeSBOM(doc *v2_3.Document, m *merge) error {
 		}
 	}
 
-	buf, err := json.MarshalIndent(doc, "", " ")
+	var buf []byte
+	var err error
+
+	if m.settings.Output.Pretty {
+		indent := " "
+		if m.settings.Output.Indent != "" {
+			indent = m.settings.Output.Indent
+		}
+		buf, err = json.MarshalIndent(doc, "", indent)
+	} else {
+		buf, err = json.Marshal(doc)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,167 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cpe
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Result is a cpe derived from a purl. Ambiguous is set with a reason when
+// the vendor or version had to be guessed, such cpes are best effort and
+// may not match the vulnerability databases.
+type Result struct {
+	CPE       string
+	Ambiguous string
+}
+
+// codeHosts are the hosts where the first path segment of a module is its
+// owner.
+var codeHosts = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+}
+
+// FromPurl derives a cpe 2.3 string from a purl of the form
+// pkg:type/namespace/name@version. The vendor comes from the namespace, the
+// owner for github purls and for golang or swift modules on a code host, the
+// organization for maven group ids, or from the name for ecosystems without
+// namespaces such as npm or pypi. Modules on other hosts, such as
+// golang.org/x/net, get the domain name as a guessed vendor.
+func FromPurl(purl string) (Result, error) {
+	typ, namespace, name, version, err := parsePurl(purl)
+	if err != nil {
+		return Result{}, err
+	}
+
+	reasons := []string{}
+
+	vendor := name
+	switch {
+	case namespace == "":
+		reasons = append(reasons, "vendor guessed from the name")
+	case typ == "maven":
+		// org.apache.logging.log4j -> apache
+		parts := strings.Split(namespace, ".")
+		vendor = parts[0]
+		if len(parts) > 1 {
+			vendor = parts[1]
+		}
+	case typ == "golang" || typ == "swift":
+		var guessed bool
+		vendor, guessed = hostPathVendor(namespace)
+		if guessed {
+			reasons = append(reasons, "vendor guessed from the module host")
+		}
+	default:
+		parts := strings.Split(namespace, "/")
+		vendor = strings.TrimPrefix(parts[len(parts)-1], "@")
+	}
+
+	switch typ {
+	case "deb", "rpm", "apk", "alpm":
+		reasons = append(reasons, fmt.Sprintf("%s namespace is the distribution not the vendor", typ))
+	}
+
+	// go module versions carry a v prefix which cpe dictionaries omit
+	if typ == "golang" && len(version) > 1 && version[0] == 'v' && version[1] >= '0' && version[1] <= '9' {
+		version = version[1:]
+	}
+
+	cpeVersion := "*"
+	if version == "" {
+		reasons = append(reasons, "no version")
+	} else {
+		cpeVersion = escape(version)
+	}
+
+	return Result{
+		CPE:       fmt.Sprintf("cpe:2.3:a:%s:%s:%s:*:*:*:*:*:*:*", escape(vendor), escape(name), cpeVersion),
+		Ambiguous: strings.Join(reasons, ", "),
+	}, nil
+}
+
+// hostPathVendor returns the vendor of a namespace which starts with a host,
+// e.g. github.com/acme or golang.org/x. It is the owner on code hosts and the
+// domain name, go.uber.org -> uber, on other hosts which is reported as a
+// guess. Namespaces without a host use their last segment.
+func hostPathVendor(namespace string) (string, bool) {
+	parts := strings.Split(namespace, "/")
+	host := strings.ToLower(parts[0])
+	if !strings.Contains(host, ".") {
+		return parts[len(parts)-1], false
+	}
+
+	if codeHosts[host] && len(parts) > 1 {
+		return parts[1], false
+	}
+
+	labels := strings.Split(host, ".")
+	return labels[len(labels)-2], true
+}
+
+func parsePurl(purl string) (typ, namespace, name, version string, err error) {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return "", "", "", "", fmt.Errorf("invalid purl %s: missing pkg: scheme", purl)
+	}
+
+	rest := strings.TrimPrefix(purl, "pkg:")
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+	rest = strings.TrimLeft(rest, "/")
+
+	if i := strings.LastIndex(rest, "@"); i > strings.LastIndex(rest, "/") {
+		rest, version = rest[:i], rest[i+1:]
+	}
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[len(parts)-1] == "" {
+		return "", "", "", "", fmt.Errorf("invalid purl %s: missing type or name", purl)
+	}
+
+	unescape := func(s string) string {
+		if u, err := url.PathUnescape(s); err == nil {
+			return u
+		}
+		return s
+	}
+
+	typ = strings.ToLower(parts[0])
+	name = unescape(parts[len(parts)-1])
+	namespace = unescape(strings.Join(parts[1:len(parts)-1], "/"))
+	version = unescape(version)
+
+	return typ, namespace, name, version, nil
+}
+
+// escape lowercases a cpe component and quotes the characters which are
+// special in the formatted string binding.
+func escape(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('_')
+		default:
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cpe
+
+import "testing"
+
+func TestFromPurl(t *testing.T) {
+	tests := []struct {
+		name      string
+		purl      string
+		want      string
+		ambiguous string
+		wantErr   string
+	}{
+		{"github", "pkg:github/acme/app@1.0.0", "cpe:2.3:a:acme:app:1.0.0:*:*:*:*:*:*:*", "", ""},
+		{"golang v prefix", "pkg:golang/github.com/acme/mod@v1.2.3", "cpe:2.3:a:acme:mod:1.2.3:*:*:*:*:*:*:*", "", ""},
+		{"golang v not followed by a number", "pkg:golang/github.com/acme/mod@vnext", "cpe:2.3:a:acme:mod:vnext:*:*:*:*:*:*:*", "", ""},
+		{"golang code host owner", "pkg:golang/gitlab.com/acme/tool@v1.0.0", "cpe:2.3:a:acme:tool:1.0.0:*:*:*:*:*:*:*", "", ""},
+		{"golang host path", "pkg:golang/golang.org/x/net@v0.17.0", "cpe:2.3:a:golang:net:0.17.0:*:*:*:*:*:*:*", "vendor guessed from the module host", ""},
+		{"golang sub domain host", "pkg:golang/go.uber.org/zap@v1.27.0", "cpe:2.3:a:uber:zap:1.27.0:*:*:*:*:*:*:*", "vendor guessed from the module host", ""},
+		{"golang code host without owner", "pkg:golang/github.com/mod@v1.0.0", "cpe:2.3:a:github:mod:1.0.0:*:*:*:*:*:*:*", "vendor guessed from the module host", ""},
+		{"golang namespace without host", "pkg:golang/acme/mod@v1.0.0", "cpe:2.3:a:acme:mod:1.0.0:*:*:*:*:*:*:*", "", ""},
+		{"swift code host owner", "pkg:swift/github.com/apple/swift-nio@2.58.0", "cpe:2.3:a:apple:swift-nio:2.58.0:*:*:*:*:*:*:*", "", ""},
+		{"maven organization", "pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1", "cpe:2.3:a:apache:log4j-core:2.17.1:*:*:*:*:*:*:*", "", ""},
+		{"maven single part group", "pkg:maven/junit/junit@4.13.2", "cpe:2.3:a:junit:junit:4.13.2:*:*:*:*:*:*:*", "", ""},
+		{"npm scope", "pkg:npm/%40angular/core@16.0.0", "cpe:2.3:a:angular:core:16.0.0:*:*:*:*:*:*:*", "", ""},
+		{"qualifiers and subpath", "pkg:npm/lodash@4.17.21?arch=x64#lib/index.js", "cpe:2.3:a:lodash:lodash:4.17.21:*:*:*:*:*:*:*", "vendor guessed from the name", ""},
+		{"no namespace", "pkg:pypi/requests@2.31.0", "cpe:2.3:a:requests:requests:2.31.0:*:*:*:*:*:*:*", "vendor guessed from the name", ""},
+		{"distribution namespace", "pkg:deb/debian/openssl@3.0.11", "cpe:2.3:a:debian:openssl:3.0.11:*:*:*:*:*:*:*", "deb namespace is the distribution not the vendor", ""},
+		{"no version", "pkg:github/acme/app", "cpe:2.3:a:acme:app:*:*:*:*:*:*:*:*", "no version", ""},
+		{"several reasons", "pkg:pypi/requests", "cpe:2.3:a:requests:requests:*:*:*:*:*:*:*:*", "vendor guessed from the name, no version", ""},
+		{"escaped characters", "pkg:github/Acme/My%20App@1.0+build", "cpe:2.3:a:acme:my_app:1.0\\+build:*:*:*:*:*:*:*", "", ""},
+		{"missing scheme", "npm/lodash@4.17.21", "", "", "invalid purl npm/lodash@4.17.21: missing pkg: scheme"},
+		{"missing name", "pkg:npm", "", "", "invalid purl pkg:npm: missing type or name"},
+		{"empty name", "pkg:npm/@1.0.0", "", "", "invalid purl pkg:npm/@1.0.0: missing type or name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromPurl(tt.purl)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("FromPurl(%q) error = %v, want %q", tt.purl, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromPurl(%q) unexpected error %v", tt.purl, err)
+			}
+			if got.CPE != tt.want || got.Ambiguous != tt.ambiguous {
+				t.Errorf("FromPurl(%q) = %q, %q, want %q, %q", tt.purl, got.CPE, got.Ambiguous, tt.want, tt.ambiguous)
+			}
+		})
+	}
+}
@@ -102,3 +102,56 @@ func Detect(f io.ReadSeeker) (SBOMSpecFormat, FileFormat, error) {
 
 	return "", "", fmt.Errorf("unknown spec or format")
 }
+
+// ParseFileFormat returns the file format for a user provided name, an empty
+// name returns FileFormatUnknown which means the format is detected.
+func ParseFileFormat(name string) (FileFormat, error) {
+	switch f := FileFormat(strings.ToLower(strings.TrimSpace(name))); f {
+	case "":
+		return FileFormatUnknown, nil
+	case FileFormatJSON, FileFormatXML, FileFormatTagValue, FileFormatYAML, FileFormatRDF:
+		return f, nil
+	}
+	return FileFormatUnknown, fmt.Errorf("unsupported input format %s, supported formats are json, xml, tag-value, yaml and rdf", name)
+}
+
+// DetectWithFormat bypasses format detection and parses f as the given file
+// format. The spec is implied for xml (cyclonedx) and tag-value, yaml and rdf
+// (spdx), json documents are inspected for the spec only. An unknown format
+// falls back to Detect.
+func DetectWithFormat(f io.ReadSeeker, format FileFormat) (SBOMSpecFormat, FileFormat, error) {
+	switch format {
+	case "", FileFormatUnknown:
+		return Detect(f)
+	case FileFormatXML:
+		return SBOMSpecCDX, format, nil
+	case FileFormatTagValue, FileFormatYAML, FileFormatRDF:
+		return SBOMSpecSPDX, format, nil
+	case FileFormatJSON:
+	default:
+		return "", "", fmt.Errorf("unsupported input format %s", format)
+	}
+
+	defer f.Seek(0, io.SeekStart)
+
+	f.Seek(0, io.SeekStart)
+
+	var doc struct {
+		ID          string `json:"SPDXID"`
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+	}
+	if err := json.NewDecoder(f).Decode(&doc); err != nil {
+		return "", "", fmt.Errorf("unable to parse input as json: %w", err)
+	}
+
+	switch {
+	case strings.HasPrefix(doc.ID, "SPDX") || strings.HasPrefix(doc.SPDXVersion, "SPDX"):
+		return SBOMSpecSPDX, FileFormatJSON, nil
+	case strings.EqualFold(doc.BOMFormat, "CycloneDX") || doc.SpecVersion != "":
+		return SBOMSpecCDX, FileFormatJSON, nil
+	}
+
+	return "", "", fmt.Errorf("unknown spec for json input")
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detect
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+const (
+	cdxJSON  = `{"bomFormat": "CycloneDX", "specVersion": "1.5", "version": 1}`
+	spdxJSON = `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "name": "doc"}`
+	cdxXML   = `<?xml version="1.0"?><bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1"></bom>`
+	spdxTV   = "SPDXVersion: SPDX-2.3\nDataLicense: CC0-1.0\n"
+	spdxYAML = "spdxVersion: SPDX-2.3\nSPDXID: SPDXRef-DOCUMENT\n"
+)
+
+func TestParseFileFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FileFormat
+		wantErr bool
+	}{
+		{"empty is detected", "", FileFormatUnknown, false},
+		{"json", "json", FileFormatJSON, false},
+		{"xml", "xml", FileFormatXML, false},
+		{"tag-value", "tag-value", FileFormatTagValue, false},
+		{"yaml", "yaml", FileFormatYAML, false},
+		{"rdf", "rdf", FileFormatRDF, false},
+		{"upper case", "JSON", FileFormatJSON, false},
+		{"mixed case with spaces", " Tag-Value ", FileFormatTagValue, false},
+		{"unknown", "csv", FileFormatUnknown, true},
+		{"unknown literal", "unknown", FileFormatUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFileFormat(tt.input)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "unsupported input format "+tt.input) {
+					t.Fatalf("ParseFileFormat(%q) error = %v, want unsupported input format", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFileFormat(%q) unexpected error %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFileFormat(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectWithFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		format     FileFormat
+		wantSpec   SBOMSpecFormat
+		wantFormat FileFormat
+		wantErr    string
+	}{
+		{"unknown detects cyclonedx json", cdxJSON, FileFormatUnknown, SBOMSpecCDX, FileFormatJSON, ""},
+		{"empty detects spdx json", spdxJSON, "", SBOMSpecSPDX, FileFormatJSON, ""},
+		{"unknown detects cyclonedx xml", cdxXML, FileFormatUnknown, SBOMSpecCDX, FileFormatXML, ""},
+		{"unknown detects tag-value", spdxTV, FileFormatUnknown, SBOMSpecSPDX, FileFormatTagValue, ""},
+		{"unknown detects yaml", spdxYAML, FileFormatUnknown, SBOMSpecSPDX, FileFormatYAML, ""},
+		{"json finds cyclonedx", cdxJSON, FileFormatJSON, SBOMSpecCDX, FileFormatJSON, ""},
+		{"json finds spdx", spdxJSON, FileFormatJSON, SBOMSpecSPDX, FileFormatJSON, ""},
+		{"json finds spdx by version", `{"spdxVersion": "SPDX-2.3"}`, FileFormatJSON, SBOMSpecSPDX, FileFormatJSON, ""},
+		{"json finds cyclonedx by version", `{"specVersion": "1.5"}`, FileFormatJSON, SBOMSpecCDX, FileFormatJSON, ""},
+		{"xml implies cyclonedx", spdxTV, FileFormatXML, SBOMSpecCDX, FileFormatXML, ""},
+		{"tag-value implies spdx", cdxJSON, FileFormatTagValue, SBOMSpecSPDX, FileFormatTagValue, ""},
+		{"yaml implies spdx", cdxJSON, FileFormatYAML, SBOMSpecSPDX, FileFormatYAML, ""},
+		{"rdf implies spdx", cdxJSON, FileFormatRDF, SBOMSpecSPDX, FileFormatRDF, ""},
+		{"json which does not parse", spdxTV, FileFormatJSON, "", "", "unable to parse input as json"},
+		{"json without a spec", `{"name": "doc"}`, FileFormatJSON, "", "", "unknown spec for json input"},
+		{"unsupported format", cdxJSON, FileFormat("csv"), "", "", "unsupported input format csv"},
+		{"case variant is not parsed", cdxJSON, FileFormat("JSON"), "", "", "unsupported input format JSON"},
+		{"nothing detected", "hello", FileFormatUnknown, "", "", "unknown spec or format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := strings.NewReader(tt.content)
+			spec, format, err := DetectWithFormat(r, tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DetectWithFormat(%q) error = %v, want %q", tt.format, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectWithFormat(%q) unexpected error %v", tt.format, err)
+			}
+			if spec != tt.wantSpec || format != tt.wantFormat {
+				t.Errorf("DetectWithFormat(%q) = %s, %s, want %s, %s", tt.format, spec, format, tt.wantSpec, tt.wantFormat)
+			}
+			if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+				t.Errorf("DetectWithFormat(%q) left the reader at %d, want 0", tt.format, pos)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package edit
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +13,7 @@ import (
 	"github.com/interlynk-io/sbomasm/pkg/detect"
 	liclib "github.com/interlynk-io/sbomasm/pkg/licenses"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
 )
 
 var cdx_strings_to_types = map[string]cydx.ComponentType{
@@ -55,7 +55,7 @@ var cdx_lifecycle_phases = map[string]cydx.LifecyclePhase{
 func cdxEdit(c *configParams) error {
 	log := logger.FromContext(*c.ctx)
 
-	bom, err := loadCdxBom(*c.ctx, c.inputFilePath)
+	bom, err := sbom.LoadCdx(*c.ctx, c.inputFilePath, c.inputFormat, c.limits)
 	if err != nil {
 		return err
 	}
@@ -78,45 +78,6 @@ func cdxEdit(c *configParams) error {
 	return writeCdxBom(doc.bom, c)
 }
 
-func loadCdxBom(ctx context.Context, path string) (*cydx.BOM, error) {
-	log := logger.FromContext(ctx)
-
-	var err error
-	var bom *cydx.BOM
-
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	spec, format, err := detect.Detect(f)
-	if err != nil {
-		return nil, err
-	}
-
-	log.Debugf("loading bom:%s spec:%s format:%s", path, spec, format)
-
-	switch format {
-	case detect.FileFormatJSON:
-		bom = new(cydx.BOM)
-		decoder := cydx.NewBOMDecoder(f, cydx.BOMFileFormatJSON)
-		if err = decoder.Decode(bom); err != nil {
-			return nil, err
-		}
-	case detect.FileFormatXML:
-		bom = new(cydx.BOM)
-		decoder := cydx.NewBOMDecoder(f, cydx.BOMFileFormatXML)
-		if err = decoder.Decode(bom); err != nil {
-			return nil, err
-		}
-	default:
-		panic("unsupported file format") // TODO: return error instead of panic
-	}
-
-	return bom, nil
-}
-
 func writeCdxBom(bom *cydx.BOM, c *configParams) error {
 	var f io.Writer
 
@@ -139,7 +100,7 @@ func writeCdxBom(bom *cydx.BOM, c *configParams) error {
 	}
 	defer inf.Close()
 
-	_, format, err := detect.Detect(inf)
+	_, format, err := detect.DetectWithFormat(inf, c.inputFormat)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	cydx "github.com/CycloneDX/cyclonedx-go"
+	cpelib "github.com/interlynk-io/sbomasm/pkg/cpe"
+	liclib "github.com/interlynk-io/sbomasm/pkg/licenses"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
 	"github.com/samber/lo"
 )
@@ -55,6 +57,8 @@ func (d *cdxEditDoc) update() {
 		{"repository", d.repository},
 		{"type", d.typ},
 		{"timeStamp", d.timeStamp},
+		{"normalizeLicenses", d.normalizeLicenses},
+		{"cpeFromPurl", d.cpeFromPurl},
 	}
 
 	for _, item := range updateFuncs {
@@ -144,6 +148,23 @@ func (d *cdxEditDoc) repository() error {
 		return errNoConfiguration
 	}
 
+	if d.c.shouldRemove("repository") {
+		notVcs := func(x cydx.ExternalReference, _ int) bool {
+			return x.Type != cydx.ERTypeVCS
+		}
+
+		if d.c.search.subject != "document" {
+			if d.comp.ExternalReferences != nil {
+				*d.comp.ExternalReferences = lo.Filter(*d.comp.ExternalReferences, notVcs)
+			}
+		} else {
+			if d.bom.ExternalReferences != nil {
+				*d.bom.ExternalReferences = lo.Filter(*d.bom.ExternalReferences, notVcs)
+			}
+		}
+		return nil
+	}
+
 	vcs := cydx.ExternalReference{
 		Type: cydx.ERTypeVCS,
 		URL:  d.c.repository,
@@ -236,6 +257,11 @@ func (d *cdxEditDoc) description() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("description") {
+		d.comp.Description = ""
+		return nil
+	}
+
 	if d.c.onMissing() {
 		if d.comp.Description == "" {
 			d.comp.Description = d.c.description
@@ -256,6 +282,11 @@ func (d *cdxEditDoc) copyright() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("copyright") {
+		d.comp.Copyright = ""
+		return nil
+	}
+
 	if d.c.onMissing() {
 		if d.comp.Copyright == "" {
 			d.comp.Copyright = d.c.copyright
@@ -574,6 +605,11 @@ func (d *cdxEditDoc) purl() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("purl") {
+		d.comp.PackageURL = ""
+		return nil
+	}
+
 	if d.c.onMissing() {
 		if d.comp.PackageURL == "" {
 			d.comp.PackageURL = d.c.purl
@@ -594,6 +630,11 @@ func (d *cdxEditDoc) cpe() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("cpe") {
+		d.comp.CPE = ""
+		return nil
+	}
+
 	if d.c.onMissing() {
 		if d.comp.CPE == "" {
 			d.comp.CPE = d.c.cpe
@@ -717,3 +758,122 @@ func (d *cdxEditDoc) authors() error {
 	}
 	return nil
 }
+
+func (d *cdxEditDoc) normalizeLicenses() error {
+	if !d.c.shouldNormalizeLicenses() {
+		return errNoConfiguration
+	}
+
+	unmapped := []string{}
+
+	normalize := func(lics *cydx.Licenses) {
+		for i := range lo.FromPtr(lics) {
+			lc := &(*lics)[i]
+
+			if lc.Expression != "" {
+				expr, ok := liclib.NormalizeExpression(lc.Expression)
+				if !ok {
+					unmapped = append(unmapped, lc.Expression)
+					continue
+				}
+				lc.Expression = expr
+				continue
+			}
+
+			if lc.License == nil {
+				continue
+			}
+
+			value := lc.License.ID
+			if value == "" {
+				value = lc.License.Name
+			}
+
+			id, ok := liclib.NormalizeLicense(value)
+			if !ok {
+				unmapped = append(unmapped, value)
+				continue
+			}
+
+			// LicenseRef- values are not valid cdx license ids
+			if _, err := liclib.LookupSpdxLicense(id); err != nil {
+				continue
+			}
+
+			// a cdx license carries either an id or a name
+			lc.License.ID = id
+			lc.License.Name = ""
+		}
+	}
+
+	var walk func(comps *[]cydx.Component)
+	walk = func(comps *[]cydx.Component) {
+		for i := range lo.FromPtr(comps) {
+			normalize((*comps)[i].Licenses)
+			walk((*comps)[i].Components)
+		}
+	}
+
+	if d.c.search.subject == "document" {
+		normalize(d.bom.Metadata.Licenses)
+		if d.bom.Metadata.Component != nil {
+			normalize(d.bom.Metadata.Component.Licenses)
+			walk(d.bom.Metadata.Component.Components)
+		}
+		walk(d.bom.Components)
+	} else {
+		normalize(d.comp.Licenses)
+	}
+
+	reportUnmappedLicenses(*d.c.ctx, unmapped)
+
+	return nil
+}
+
+func (d *cdxEditDoc) cpeFromPurl() error {
+	if !d.c.shouldCpeFromPurl() {
+		return errNoConfiguration
+	}
+
+	log := logger.FromContext(*d.c.ctx)
+	ambiguous := []string{}
+
+	derive := func(comp *cydx.Component) {
+		if comp.CPE != "" || comp.PackageURL == "" {
+			return
+		}
+
+		res, err := cpelib.FromPurl(comp.PackageURL)
+		if err != nil {
+			log.Warnf("no cpe for %s: %s", comp.Name, err)
+			return
+		}
+
+		comp.CPE = res.CPE
+		if res.Ambiguous != "" {
+			ambiguous = append(ambiguous, fmt.Sprintf("%s: %s (%s)", comp.PackageURL, res.CPE, res.Ambiguous))
+		}
+	}
+
+	var walk func(comps *[]cydx.Component)
+	walk = func(comps *[]cydx.Component) {
+		for i := range lo.FromPtr(comps) {
+			derive(&(*comps)[i])
+			walk((*comps)[i].Components)
+		}
+	}
+
+	if d.c.search.subject == "document" {
+		if d.bom.Metadata != nil && d.bom.Metadata.Component != nil {
+			derive(d.bom.Metadata.Component)
+			walk(d.bom.Metadata.Component.Components)
+		}
+		walk(d.bom.Components)
+	} else {
+		derive(d.comp)
+	}
+
+	reportAmbiguousCpes(*d.c.ctx, ambiguous)
+
+	return nil
+}
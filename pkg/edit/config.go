@@ -19,7 +19,12 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
+	"github.com/samber/lo"
 )
 
 var supportedSubjects map[string]bool = map[string]bool{
@@ -28,12 +33,21 @@ var supportedSubjects map[string]bool = map[string]bool{
 	"component-name-version": true,
 }
 
+var supportedRemoveFields map[string]bool = map[string]bool{
+	"purl":        true,
+	"cpe":         true,
+	"copyright":   true,
+	"description": true,
+	"repository":  true,
+}
+
 type SearchParams struct {
 	subject string
 	name    string
 	version string
 	missing bool
 	append  bool
+	remove  bool
 }
 
 type paramTuple struct {
@@ -45,6 +59,8 @@ type configParams struct {
 	ctx *context.Context
 
 	inputFilePath  string
+	inputFormat    detect.FileFormat
+	limits         limits.Limits
 	outputFilePath string
 
 	search SearchParams
@@ -65,6 +81,19 @@ type configParams struct {
 	typ         string
 
 	timestamp bool
+
+	normalizeLicenses bool
+	cpeFromPurl       bool
+
+	removeFields map[string]bool
+}
+
+func (c *configParams) shouldNormalizeLicenses() bool {
+	return c.normalizeLicenses
+}
+
+func (c *configParams) shouldCpeFromPurl() bool {
+	return c.cpeFromPurl
 }
 
 func (c *configParams) shouldTimeStamp() bool {
@@ -76,15 +105,15 @@ func (c *configParams) shouldTyp() bool {
 }
 
 func (c *configParams) shouldRepository() bool {
-	return c.repository != ""
+	return c.repository != "" || c.shouldRemove("repository")
 }
 
 func (c *configParams) shouldDescription() bool {
-	return c.description != ""
+	return c.description != "" || c.shouldRemove("description")
 }
 
 func (c *configParams) shouldCopyRight() bool {
-	return c.copyright != ""
+	return c.copyright != "" || c.shouldRemove("copyright")
 }
 
 func (c *configParams) shouldTools() bool {
@@ -100,11 +129,11 @@ func (c *configParams) shouldLicenses() bool {
 }
 
 func (c *configParams) shouldCpe() bool {
-	return c.cpe != ""
+	return c.cpe != "" || c.shouldRemove("cpe")
 }
 
 func (c *configParams) shouldPurl() bool {
-	return c.purl != ""
+	return c.purl != "" || c.shouldRemove("purl")
 }
 
 func (c *configParams) shouldAuthors() bool {
@@ -139,6 +168,10 @@ func (c *configParams) onAppend() bool {
 	return c.search.append
 }
 
+func (c *configParams) shouldRemove(field string) bool {
+	return c.search.remove && c.removeFields[field]
+}
+
 func (c *configParams) shouldSearch() bool {
 	return c.search.subject == "component-name-version"
 }
@@ -166,6 +199,14 @@ func convertToConfigParams(eParams *EditParams) (*configParams, error) {
 
 	p.inputFilePath = eParams.Input
 
+	p.limits = eParams.Limits
+
+	format, err := detect.ParseFileFormat(eParams.InputFormat)
+	if err != nil {
+		return nil, err
+	}
+	p.inputFormat = format
+
 	if eParams.Output != "" {
 		p.outputFilePath = eParams.Output
 	}
@@ -195,6 +236,16 @@ func convertToConfigParams(eParams *EditParams) (*configParams, error) {
 
 	p.search.missing = eParams.Missing
 	p.search.append = eParams.Append
+	p.search.remove = eParams.Remove
+
+	if err := validateRemove(eParams); err != nil {
+		return nil, err
+	}
+
+	p.removeFields = make(map[string]bool)
+	for _, field := range eParams.RemoveFields {
+		p.removeFields[field] = true
+	}
 
 	p.name = eParams.Name
 	p.version = eParams.Version
@@ -250,9 +301,48 @@ func convertToConfigParams(eParams *EditParams) (*configParams, error) {
 	p.typ = eParams.Type
 
 	p.timestamp = eParams.Timestamp
+	p.normalizeLicenses = eParams.NormalizeLicenses
+	p.cpeFromPurl = eParams.CpeFromPurl
 
 	return p, nil
 }
+
+func validateRemove(eParams *EditParams) error {
+	if !eParams.Remove {
+		return nil
+	}
+
+	if eParams.Missing || eParams.Append {
+		return fmt.Errorf("remove cannot be combined with missing or append")
+	}
+
+	if len(eParams.RemoveFields) == 0 {
+		fields := lo.Keys(supportedRemoveFields)
+		sort.Strings(fields)
+		return fmt.Errorf("remove requires at least one of %s", strings.Join(fields, ", "))
+	}
+
+	values := map[string]string{
+		"purl":        eParams.Purl,
+		"cpe":         eParams.Cpe,
+		"copyright":   eParams.CopyRight,
+		"description": eParams.Description,
+		"repository":  eParams.Repository,
+	}
+
+	for _, field := range eParams.RemoveFields {
+		if !supportedRemoveFields[field] {
+			return fmt.Errorf("remove is not supported for %s", field)
+		}
+
+		if values[field] != "" {
+			return fmt.Errorf("remove and a value for %s cannot both be provided", field)
+		}
+	}
+
+	return nil
+}
+
 func parseInputFormat(s string) (name string, version string) {
 	// Trim any leading/trailing whitespace
 	s = strings.TrimSpace(s)
@@ -17,7 +17,9 @@ package edit
 import (
 	"context"
 
+	"github.com/interlynk-io/sbomasm/pkg/limits"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
 )
 
 // EditParams represents the parameters for the edit command
@@ -27,11 +29,18 @@ type EditParams struct {
 	Input  string
 	Output string
 
+	// InputFormat forces the file format of the input instead of detecting it
+	InputFormat string
+
 	Subject string
 	Search  string
 
 	Append  bool
 	Missing bool
+	Remove  bool
+
+	// RemoveFields are the fields selected for clearing when Remove is set
+	RemoveFields []string
 
 	Name        string
 	Version     string
@@ -48,10 +57,17 @@ type EditParams struct {
 	Description string
 	Repository  string
 	Type        string
+
+	NormalizeLicenses bool
+	CpeFromPurl       bool
+
+	Limits limits.Limits
 }
 
 func NewEditParams() *EditParams {
-	return &EditParams{}
+	return &EditParams{
+		Limits: limits.Default(),
+	}
 }
 
 func Edit(eParams *EditParams) error {
@@ -64,7 +80,7 @@ func Edit(eParams *EditParams) error {
 	}
 	log.Debugf("config %+v", c)
 
-	spec, format, err := detectSbom(eParams.Input)
+	spec, format, err := sbom.Detect(eParams.Input, c.inputFormat, c.limits)
 	if err != nil {
 		return err
 	}
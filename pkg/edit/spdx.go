@@ -1,7 +1,6 @@
 package edit
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -9,12 +8,11 @@ import (
 	"strings"
 
 	"github.com/interlynk-io/sbomasm/pkg/detect"
-	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
 	"github.com/spdx/tools-golang/spdx"
 
 	"github.com/samber/lo"
 	spdx_json "github.com/spdx/tools-golang/json"
-	spdx_rdf "github.com/spdx/tools-golang/rdf"
 	"github.com/spdx/tools-golang/spdx/common"
 	spdx_tv "github.com/spdx/tools-golang/tagvalue"
 	spdx_yaml "github.com/spdx/tools-golang/yaml"
@@ -53,7 +51,7 @@ var spdx_strings_to_types = map[string]string{
 func spdxEdit(c *configParams) error {
 	// log := logger.FromContext(*c.ctx)
 
-	bom, err := loadSpdxSbom(*c.ctx, c.inputFilePath)
+	bom, err := sbom.LoadSpdx(*c.ctx, c.inputFilePath, c.inputFormat, c.limits)
 	if err != nil {
 		return err
 	}
@@ -63,49 +61,17 @@ func spdxEdit(c *configParams) error {
 		return errors.New("failed to create spdx edit document")
 	}
 
-	doc.update()
-
-	return writeSpdxSbom(doc.bom, c)
-}
-
-func loadSpdxSbom(ctx context.Context, path string) (*spdx.Document, error) {
-	log := logger.FromContext(ctx)
-
-	var d common.AnyDocument
-	var err error
-
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	spec, format, err := detect.Detect(f)
-	if err != nil {
-		return nil, err
+	if c.search.subject == "primary-component" && doc.pkg == nil {
+		return errors.New("primary package not found, the document has no DESCRIBES relationship and no package could be picked as primary")
 	}
 
-	log.Debugf("loading bom:%s spec:%s format:%s", path, spec, format)
-
-	switch format {
-	case detect.FileFormatJSON:
-		d, err = spdx_json.Read(f)
-	case detect.FileFormatTagValue:
-		d, err = spdx_tv.Read(f)
-	case detect.FileFormatYAML:
-		d, err = spdx_yaml.Read(f)
-	case detect.FileFormatRDF:
-		d, err = spdx_rdf.Read(f)
-	default:
-		panic("unsupported spdx format")
-
+	if c.shouldSearch() && doc.pkg == nil {
+		return fmt.Errorf("package not found: %s, %s", c.search.name, c.search.version)
 	}
 
-	if err != nil {
-		return nil, err
-	}
+	doc.update()
 
-	return d.(*spdx.Document), nil
+	return writeSpdxSbom(doc.bom, c)
 }
 
 func writeSpdxSbom(doc common.AnyDocument, m *configParams) error {
@@ -127,7 +93,7 @@ func writeSpdxSbom(doc common.AnyDocument, m *configParams) error {
 	}
 	defer inf.Close()
 
-	_, format, err := detect.Detect(inf)
+	_, format, err := detect.DetectWithFormat(inf, m.inputFormat)
 	if err != nil {
 		return err
 	}
@@ -173,6 +139,10 @@ func spdxFindPkg(doc *spdx.Document, c *configParams, primaryPackage bool) (*spd
 				}
 			}
 		}
+
+		if pkg := sbom.FallbackPrimaryPackage(doc); pkg != nil {
+			return pkg, nil
+		}
 	}
 
 	return nil, errors.New("package not found")
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	cpelib "github.com/interlynk-io/sbomasm/pkg/cpe"
+	liclib "github.com/interlynk-io/sbomasm/pkg/licenses"
 	"github.com/interlynk-io/sbomasm/pkg/logger"
 	"github.com/samber/lo"
 	"github.com/spdx/tools-golang/spdx"
@@ -65,6 +67,8 @@ func (d *spdxEditDoc) update() {
 		{"repository", d.repository},
 		{"type", d.typ},
 		{"timeStamp", d.timeStamp},
+		{"normalizeLicenses", d.normalizeLicenses},
+		{"cpeFromPurl", d.cpeFromPurl},
 	}
 
 	for _, item := range updateFuncs {
@@ -197,6 +201,13 @@ func (d *spdxEditDoc) purl() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("purl") {
+		d.pkg.PackageExternalReferences = lo.Reject(d.pkg.PackageExternalReferences, func(x *spdx.PackageExternalReference, _ int) bool {
+			return strings.ToLower(x.RefType) == "purl"
+		})
+		return nil
+	}
+
 	purl := spdx.PackageExternalReference{
 		Category: "PACKAGE-MANAGER",
 		RefType:  "purl",
@@ -254,6 +265,13 @@ func (d *spdxEditDoc) cpe() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("cpe") {
+		d.pkg.PackageExternalReferences = lo.Reject(d.pkg.PackageExternalReferences, func(x *spdx.PackageExternalReference, _ int) bool {
+			return strings.HasPrefix(strings.ToLower(x.RefType), "cpe")
+		})
+		return nil
+	}
+
 	cpe := spdx.PackageExternalReference{
 		Category: "SECURITY",
 		RefType:  "cpe23Type",
@@ -459,6 +477,11 @@ func (d *spdxEditDoc) copyright() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("copyright") {
+		d.pkg.PackageCopyrightText = "NOASSERTION"
+		return nil
+	}
+
 	if d.c.onMissing() {
 		if d.pkg.PackageCopyrightText == "" {
 			d.pkg.PackageCopyrightText = d.c.copyright
@@ -475,6 +498,15 @@ func (d *spdxEditDoc) description() error {
 		return errNoConfiguration
 	}
 
+	if d.c.shouldRemove("description") {
+		if d.c.search.subject == "document" {
+			d.bom.DocumentComment = ""
+		} else {
+			d.pkg.PackageDescription = ""
+		}
+		return nil
+	}
+
 	if d.c.onMissing() {
 		if d.c.search.subject == "document" {
 			if d.bom.DocumentComment == "" {
@@ -505,6 +537,11 @@ func (d *spdxEditDoc) repository() error {
 		return errNotSupported
 	}
 
+	if d.c.shouldRemove("repository") {
+		d.pkg.PackageDownloadLocation = "NOASSERTION"
+		return nil
+	}
+
 	if d.c.onMissing() {
 		if d.pkg.PackageDownloadLocation == "" {
 			d.pkg.PackageDownloadLocation = d.c.repository
@@ -595,3 +632,89 @@ func (d *spdxEditDoc) lifeCycles() error {
 	}
 	return nil
 }
+
+func (d *spdxEditDoc) normalizeLicenses() error {
+	if !d.c.shouldNormalizeLicenses() {
+		return errNoConfiguration
+	}
+
+	unmapped := []string{}
+
+	normalize := func(value *string) {
+		lower := strings.ToLower(*value)
+		if *value == "" || lower == "noassertion" || lower == "none" {
+			return
+		}
+
+		expr, ok := liclib.NormalizeExpression(*value)
+		if !ok {
+			unmapped = append(unmapped, *value)
+			return
+		}
+		*value = expr
+	}
+
+	pkgs := d.bom.Packages
+	if d.c.search.subject != "document" {
+		pkgs = []*spdx.Package{d.pkg}
+	}
+
+	for _, pkg := range pkgs {
+		normalize(&pkg.PackageLicenseConcluded)
+		normalize(&pkg.PackageLicenseDeclared)
+	}
+
+	reportUnmappedLicenses(*d.c.ctx, unmapped)
+
+	return nil
+}
+
+func (d *spdxEditDoc) cpeFromPurl() error {
+	if !d.c.shouldCpeFromPurl() {
+		return errNoConfiguration
+	}
+
+	log := logger.FromContext(*d.c.ctx)
+	ambiguous := []string{}
+
+	pkgs := d.bom.Packages
+	if d.c.search.subject != "document" {
+		pkgs = []*spdx.Package{d.pkg}
+	}
+
+	for _, pkg := range pkgs {
+		purl := ""
+		hasCpe := false
+		for _, ref := range pkg.PackageExternalReferences {
+			switch ref.RefType {
+			case "purl":
+				purl = ref.Locator
+			case "cpe23Type":
+				hasCpe = true
+			}
+		}
+
+		if hasCpe || purl == "" {
+			continue
+		}
+
+		res, err := cpelib.FromPurl(purl)
+		if err != nil {
+			log.Warnf("no cpe for %s: %s", pkg.PackageName, err)
+			continue
+		}
+
+		pkg.PackageExternalReferences = append(pkg.PackageExternalReferences, &spdx.PackageExternalReference{
+			Category: "SECURITY",
+			RefType:  "cpe23Type",
+			Locator:  res.CPE,
+		})
+		if res.Ambiguous != "" {
+			ambiguous = append(ambiguous, fmt.Sprintf("%s: %s (%s)", purl, res.CPE, res.Ambiguous))
+		}
+	}
+
+	reportAmbiguousCpes(*d.c.ctx, ambiguous)
+
+	return nil
+}
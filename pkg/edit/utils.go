@@ -1,33 +1,45 @@
 package edit
 
 import (
+	"context"
 	"errors"
-	"os"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/samber/lo"
 )
 
 var errNoConfiguration = errors.New("no configuration provided")
 var errNotSupported = errors.New("not supported")
 var errInvalidInput = errors.New("invalid input data")
 
-func detectSbom(path string) (string, string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return "", "", err
-	}
-	defer f.Close()
-
-	spec, format, err := detect.Detect(f)
-	if err != nil {
-		return "", "", err
-	}
-	return string(spec), string(format), nil
-}
-
 func utcNowTime() string {
 	location, _ := time.LoadLocation("UTC")
 	locationTime := time.Now().In(location)
 	return locationTime.Format(time.RFC3339)
 }
+
+func reportAmbiguousCpes(ctx context.Context, ambiguous []string) {
+	log := logger.FromContext(ctx)
+
+	if len(ambiguous) == 0 {
+		return
+	}
+
+	sort.Strings(ambiguous)
+	log.Warnf("cpes derived from purls are ambiguous, review them (%d):\n  %s", len(ambiguous), strings.Join(ambiguous, "\n  "))
+}
+
+func reportUnmappedLicenses(ctx context.Context, unmapped []string) {
+	log := logger.FromContext(ctx)
+
+	unmapped = lo.Uniq(unmapped)
+	if len(unmapped) == 0 {
+		return
+	}
+
+	sort.Strings(unmapped)
+	log.Warnf("license values not mapped to an spdx id (%d): %s", len(unmapped), strings.Join(unmapped, ", "))
+}
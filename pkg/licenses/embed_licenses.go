@@ -195,4 +195,5 @@ func init() {
 	if err != nil {
 		log.Printf("Failed to load about code license: %v", err)
 	}
+	buildLicenseIndex()
 }
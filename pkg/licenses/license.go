@@ -16,6 +16,7 @@ package licenses
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	go_spdx "github.com/github/go-spdx/v2/spdxexp"
@@ -94,6 +95,20 @@ func IsSpdxExpression(licenseKey string) bool {
 	return true
 }
 
+// ValidateExpression returns an error when the expression does not parse as
+// an spdx license expression of known license and exception ids.
+func ValidateExpression(expression string) (err error) {
+	// the parser panics on some malformed expressions e.g "MIT AND ("
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid license expression %s", expression)
+		}
+	}()
+
+	_, err = go_spdx.ExtractLicenses(expression)
+	return err
+}
+
 func LookupSpdxLicense(licenseKey string) (License, error) {
 	if licenseKey == "" {
 		return nil, errors.New("license not found")
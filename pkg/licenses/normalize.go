@@ -0,0 +1,173 @@
+// Copyright 2023 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package licenses
+
+import (
+	"regexp"
+	"strings"
+)
+
+// licenseAliases maps commonly seen non canonical license values to their
+// SPDX id. Keys are in the form produced by aliasKey. Values which leave the
+// version open, e.g "Apache" or "GPLv2" without only or or-later, are not
+// mapped since picking one would change the licensing statement.
+var licenseAliases = map[string]string{
+	"apache2":                             "Apache-2.0",
+	"apache 2":                            "Apache-2.0",
+	"apache2.0":                           "Apache-2.0",
+	"apache 2.0":                          "Apache-2.0",
+	"apache v2":                           "Apache-2.0",
+	"apache v2.0":                         "Apache-2.0",
+	"apache version 2.0":                  "Apache-2.0",
+	"apache license version 2.0":          "Apache-2.0",
+	"apache software license 2.0":         "Apache-2.0",
+	"apache software license version 2.0": "Apache-2.0",
+	"asl 2.0":                             "Apache-2.0",
+	"asl2":                                "Apache-2.0",
+	"al2":                                 "Apache-2.0",
+	"apache 1.1":                          "Apache-1.1",
+	"mit/x11":                             "MIT",
+	"expat":                               "MIT",
+	"the mit":                             "MIT",
+	"bsd 2 clause":                        "BSD-2-Clause",
+	"bsd2":                                "BSD-2-Clause",
+	"simplified bsd":                      "BSD-2-Clause",
+	"freebsd":                             "BSD-2-Clause",
+	"bsd 3 clause":                        "BSD-3-Clause",
+	"bsd3":                                "BSD-3-Clause",
+	"new bsd":                             "BSD-3-Clause",
+	"modified bsd":                        "BSD-3-Clause",
+	"revised bsd":                         "BSD-3-Clause",
+	"gplv2+":                              "GPL-2.0-or-later",
+	"gpl 2.0+":                            "GPL-2.0-or-later",
+	"gplv3+":                              "GPL-3.0-or-later",
+	"gpl 3.0+":                            "GPL-3.0-or-later",
+	"lgplv2.1+":                           "LGPL-2.1-or-later",
+	"lgpl 2.1+":                           "LGPL-2.1-or-later",
+	"lgplv3+":                             "LGPL-3.0-or-later",
+	"agplv3+":                             "AGPL-3.0-or-later",
+	"mpl2":                                "MPL-2.0",
+	"mpl 2":                               "MPL-2.0",
+	"mplv2":                               "MPL-2.0",
+	"mpl 1.1":                             "MPL-1.1",
+	"epl 1":                               "EPL-1.0",
+	"epl2":                                "EPL-2.0",
+	"epl 2":                               "EPL-2.0",
+	"eclipse public 2.0":                  "EPL-2.0",
+	"cddl 1.1":                            "CDDL-1.1",
+	"cc0":                                 "CC0-1.0",
+	"cc0 1.0 universal":                   "CC0-1.0",
+	"psf":                                 "PSF-2.0",
+	"python software foundation":          "PSF-2.0",
+	"boost":                               "BSL-1.0",
+	"boost software":                      "BSL-1.0",
+	"isc":                                 "ISC",
+	"zlib/libpng":                         "Zlib",
+	"unlicensed":                          "Unlicense",
+}
+
+var (
+	licenseListByLowerID   = map[string]string{}
+	licenseListByLowerName = map[string]string{}
+
+	aliasSeparators    = regexp.MustCompile(`[\s_,]+`)
+	expressionOperator = regexp.MustCompile(`\s+(?i:AND|OR|WITH)\s+`)
+)
+
+func buildLicenseIndex() {
+	for id, l := range licenseList {
+		licenseListByLowerID[strings.ToLower(id)] = id
+		licenseListByLowerName[strings.ToLower(l.name)] = id
+	}
+}
+
+// aliasKey lowercases the value, collapses separators and drops the filler
+// words which appear in license names but not in spdx ids.
+func aliasKey(value string) string {
+	key := strings.ToLower(strings.TrimSpace(value))
+	key = aliasSeparators.ReplaceAllString(key, " ")
+	key = strings.TrimSuffix(key, " license")
+	key = strings.TrimPrefix(key, "license ")
+	return strings.TrimSpace(strings.ReplaceAll(key, " license ", " "))
+}
+
+// NormalizeLicense maps a license id, license name or a common alias to its
+// canonical SPDX id. It returns false when the value could not be mapped.
+func NormalizeLicense(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+
+	if _, ok := licenseList[value]; ok {
+		return value, true
+	}
+
+	if strings.HasPrefix(value, "LicenseRef-") || strings.HasPrefix(value, "DocumentRef-") {
+		return value, true
+	}
+
+	lower := strings.ToLower(value)
+	if id, ok := licenseListByLowerID[lower]; ok {
+		return id, true
+	}
+
+	if id, ok := licenseListByLowerName[lower]; ok {
+		return id, true
+	}
+
+	key := aliasKey(value)
+	if id, ok := licenseAliases[key]; ok {
+		return id, true
+	}
+
+	// e.g "apache 2.0" and "MPL 2.0" only differ from the spdx id by the separator
+	if id, ok := licenseListByLowerID[strings.ReplaceAll(key, " ", "-")]; ok {
+		return id, true
+	}
+
+	return "", false
+}
+
+// NormalizeExpression normalizes every license of an expression joined by
+// AND, OR or WITH. Expressions with parentheses are only accepted when they
+// are already valid. It returns false when any license could not be mapped.
+func NormalizeExpression(expression string) (string, bool) {
+	expression = strings.TrimSpace(expression)
+
+	if strings.ContainsAny(expression, "()") {
+		return expression, ValidateExpression(expression) == nil
+	}
+
+	operators := expressionOperator.FindAllString(expression, -1)
+	terms := expressionOperator.Split(expression, -1)
+
+	var sb strings.Builder
+	for i, term := range terms {
+		id, ok := NormalizeLicense(term)
+		if !ok {
+			return expression, false
+		}
+
+		sb.WriteString(id)
+		if i < len(operators) {
+			sb.WriteString(" ")
+			sb.WriteString(strings.ToUpper(strings.TrimSpace(operators[i])))
+			sb.WriteString(" ")
+		}
+	}
+
+	return sb.String(), true
+}
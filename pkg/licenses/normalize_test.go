@@ -0,0 +1,52 @@
+// Copyright 2023 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package licenses
+
+import "testing"
+
+func TestNormalizeExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+		wantOk     bool
+	}{
+		{"spdx id", "MIT", "MIT", true},
+		{"lowercase id", "apache-2.0", "Apache-2.0", true},
+		{"license name", "MIT License", "MIT", true},
+		{"alias", "Apache 2.0", "Apache-2.0", true},
+		{"or later alias", "GPLv2+", "GPL-2.0-or-later", true},
+		{"joined aliases", "Apache2 or bsd3", "Apache-2.0 OR BSD-3-Clause", true},
+		{"with exception", "GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0", true},
+		{"license ref", "LicenseRef-internal AND MIT", "LicenseRef-internal AND MIT", true},
+		{"valid parenthesized", "(MIT OR Apache-2.0) AND BSD-3-Clause", "(MIT OR Apache-2.0) AND BSD-3-Clause", true},
+		{"unbalanced parenthesis", "MIT AND (", "MIT AND (", false},
+		{"invalid parenthesized", "(MIT OR Foo)", "(MIT OR Foo)", false},
+		{"unknown", "Foo", "Foo", false},
+		{"bare apache", "Apache", "Apache", false},
+		{"apache software", "Apache Software", "Apache Software", false},
+		{"gpl without only or later", "GPL 2", "GPL 2", false},
+		{"gplv3 without only or later", "GPLv3", "GPLv3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeExpression(tt.expression)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("NormalizeExpression(%q) = %q, %v, want %q, %v", tt.expression, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
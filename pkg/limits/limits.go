@@ -0,0 +1,91 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"fmt"
+	"os"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+)
+
+const (
+	// DefaultMaxFileSizeMB is the largest sbom file loaded by default
+	DefaultMaxFileSizeMB = 1024
+	// DefaultMaxComponents is the largest number of components or packages
+	// of an sbom loaded by default
+	DefaultMaxComponents = 1000000
+)
+
+// Limits guards the loaders against sboms too large to be held in memory,
+// a zero value disables the limit.
+type Limits struct {
+	MaxFileSizeMB int64
+	MaxComponents int
+}
+
+func Default() Limits {
+	return Limits{
+		MaxFileSizeMB: DefaultMaxFileSizeMB,
+		MaxComponents: DefaultMaxComponents,
+	}
+}
+
+// CheckFileSize returns an error when the file at path is larger than the
+// file size limit, it is meant to run before the file is read.
+func (l Limits) CheckFileSize(path string) error {
+	if l.MaxFileSizeMB <= 0 {
+		return nil
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if stat.Size() > l.MaxFileSizeMB<<20 {
+		return fmt.Errorf("sbom %s is %.1f MB which exceeds the limit of %d MB, split the sbom or raise the limit with --max-file-size, sboms are loaded in memory as streaming is not supported",
+			path, float64(stat.Size())/(1<<20), l.MaxFileSizeMB)
+	}
+
+	return nil
+}
+
+// CheckComponents returns an error when the sbom at path has more components
+// than the component limit.
+func (l Limits) CheckComponents(path string, count int) error {
+	if l.MaxComponents <= 0 || count <= l.MaxComponents {
+		return nil
+	}
+
+	return fmt.Errorf("sbom %s has %d components which exceeds the limit of %d, split the sbom or raise the limit with --max-components, sboms are loaded in memory as streaming is not supported",
+		path, count, l.MaxComponents)
+}
+
+// CountComponents returns the number of cyclonedx components including the
+// nested ones, it is the count checked by CheckComponents.
+func CountComponents(comps *[]cydx.Component) int {
+	if comps == nil {
+		return 0
+	}
+
+	count := 0
+	for _, c := range *comps {
+		count += 1 + CountComponents(c.Components)
+	}
+	return count
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+)
+
+func TestCheckFileSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sbom.json")
+	if err := os.WriteFile(path, make([]byte, 2<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		limits  Limits
+		path    string
+		wantErr string
+	}{
+		{"under the limit", Limits{MaxFileSizeMB: 3}, path, ""},
+		{"at the limit", Limits{MaxFileSizeMB: 2}, path, ""},
+		{"over the limit", Limits{MaxFileSizeMB: 1}, path, "is 2.0 MB which exceeds the limit of 1 MB"},
+		{"disabled", Limits{}, path, ""},
+		{"disabled skips the stat", Limits{}, filepath.Join(t.TempDir(), "missing.json"), ""},
+		{"missing file", Limits{MaxFileSizeMB: 1}, filepath.Join(t.TempDir(), "missing.json"), "no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.CheckFileSize(tt.path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckFileSize(%q) unexpected error %v", tt.path, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CheckFileSize(%q) error = %v, want %q", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckComponents(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		count   int
+		wantErr string
+	}{
+		{"under the limit", Limits{MaxComponents: 3}, 2, ""},
+		{"at the limit", Limits{MaxComponents: 3}, 3, ""},
+		{"over the limit", Limits{MaxComponents: 3}, 4, "sbom in.json has 4 components which exceeds the limit of 3"},
+		{"disabled", Limits{}, 4, ""},
+		{"default", Default(), DefaultMaxComponents + 1, "exceeds the limit of 1000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.CheckComponents("in.json", tt.count)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckComponents(%d) unexpected error %v", tt.count, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CheckComponents(%d) error = %v, want %q", tt.count, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCountComponents(t *testing.T) {
+	nested := []cydx.Component{
+		{Name: "a", Components: &[]cydx.Component{
+			{Name: "a1"},
+			{Name: "a2", Components: &[]cydx.Component{{Name: "a21"}}},
+		}},
+		{Name: "b"},
+	}
+
+	tests := []struct {
+		name  string
+		comps *[]cydx.Component
+		want  int
+	}{
+		{"nil", nil, 0},
+		{"empty", &[]cydx.Component{}, 0},
+		{"flat", &[]cydx.Component{{Name: "a"}, {Name: "b"}}, 2},
+		{"nested", &nested, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountComponents(tt.comps); got != tt.want {
+				t.Errorf("CountComponents() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
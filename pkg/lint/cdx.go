@@ -0,0 +1,115 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	liclib "github.com/interlynk-io/sbomasm/pkg/licenses"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/samber/lo"
+)
+
+func cdxLint(lParams *LintParams, format detect.FileFormat) ([]Issue, error) {
+	bom, err := sbom.LoadCdx(*lParams.Ctx, lParams.Input, format, lParams.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{}
+	l.cdxBom(bom)
+
+	return l.issues, nil
+}
+
+func (l *linter) cdxBom(bom *cydx.BOM) {
+	refs := map[string]int{}
+
+	if bom.Metadata == nil || bom.Metadata.Component == nil {
+		l.errorf("primary-component", "metadata has no primary component")
+	} else {
+		l.cdxComponent(bom.Metadata.Component, refs)
+	}
+
+	for i := range lo.FromPtr(bom.Components) {
+		l.cdxComponent(&(*bom.Components)[i], refs)
+	}
+
+	var walkServices func(svcs *[]cydx.Service)
+	walkServices = func(svcs *[]cydx.Service) {
+		for _, s := range lo.FromPtr(svcs) {
+			if s.BOMRef != "" {
+				refs[s.BOMRef]++
+			}
+			walkServices(s.Services)
+		}
+	}
+	walkServices(bom.Services)
+
+	dups := lo.Keys(lo.PickBy(refs, func(_ string, n int) bool { return n > 1 }))
+	sort.Strings(dups)
+	for _, ref := range dups {
+		l.errorf("duplicate-ref", "bom-ref %s is used by %d elements", ref, refs[ref])
+	}
+
+	for _, d := range lo.FromPtr(bom.Dependencies) {
+		if _, ok := refs[d.Ref]; !ok {
+			l.errorf("dangling-dependency", "dependency ref %s does not match any component or service", d.Ref)
+		}
+
+		for _, dep := range lo.FromPtr(d.Dependencies) {
+			if _, ok := refs[dep]; !ok {
+				l.errorf("dangling-dependency", "%s depends on %s which does not match any component or service", d.Ref, dep)
+			}
+		}
+	}
+}
+
+func (l *linter) cdxComponent(comp *cydx.Component, refs map[string]int) {
+	subject := fmt.Sprintf("component %s@%s", comp.Name, comp.Version)
+
+	if comp.BOMRef != "" {
+		refs[comp.BOMRef]++
+	}
+
+	// files are identified by their hashes
+	if comp.Type != cydx.ComponentTypeFile && comp.PackageURL == "" && comp.CPE == "" && comp.SWID == nil {
+		l.warnf("missing-identifier", "%s has no purl, cpe or swid", subject)
+	}
+
+	for _, lc := range lo.FromPtr(comp.Licenses) {
+		if lc.Expression != "" {
+			if err := liclib.ValidateExpression(lc.Expression); err != nil {
+				l.errorf("invalid-license", "%s has an invalid license expression %s", subject, lc.Expression)
+			}
+			continue
+		}
+
+		if lc.License == nil || lc.License.ID == "" {
+			continue
+		}
+
+		if _, err := liclib.LookupSpdxLicense(lc.License.ID); err != nil {
+			l.errorf("invalid-license", "%s has an unknown spdx license id %s", subject, lc.License.ID)
+		}
+	}
+
+	for i := range lo.FromPtr(comp.Components) {
+		l.cdxComponent(&(*comp.Components)[i], refs)
+	}
+}
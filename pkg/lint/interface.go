@@ -0,0 +1,133 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/samber/lo"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a structural problem found in an sbom, Check names the check
+// which reported it e.g "dangling-dependency".
+type Issue struct {
+	Severity Severity
+	Check    string
+	Message  string
+}
+
+// LintParams represents the parameters for the lint command
+type LintParams struct {
+	Ctx *context.Context
+
+	Input string
+
+	// InputFormat forces the file format of the input instead of detecting it
+	InputFormat string
+
+	Limits limits.Limits
+}
+
+func NewLintParams() *LintParams {
+	return &LintParams{
+		Limits: limits.Default(),
+	}
+}
+
+// Lint loads the input sbom and returns the structural problems found in it,
+// the error is only set when the sbom could not be read.
+func Lint(lParams *LintParams) ([]Issue, error) {
+	log := logger.FromContext(*lParams.Ctx)
+
+	if err := validate(lParams); err != nil {
+		return nil, err
+	}
+
+	inputFormat, err := detect.ParseFileFormat(lParams.InputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, format, err := sbom.Detect(lParams.Input, inputFormat, lParams.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("input sbom spec: %s format: %s", spec, format)
+
+	switch spec {
+	case detect.SBOMSpecCDX:
+		return cdxLint(lParams, format)
+	case detect.SBOMSpecSPDX:
+		return spdxLint(lParams, format)
+	}
+
+	return nil, fmt.Errorf("unsupported sbom spec %s", spec)
+}
+
+// Report writes the issues, one per line, followed by a summary and returns
+// the number of errors.
+func Report(w io.Writer, issues []Issue) int {
+	for _, i := range issues {
+		fmt.Fprintf(w, "%-7s %-22s %s\n", i.Severity, i.Check, i.Message)
+	}
+
+	errs := lo.CountBy(issues, func(i Issue) bool {
+		return i.Severity == SeverityError
+	})
+
+	fmt.Fprintf(w, "%d errors, %d warnings\n", errs, len(issues)-errs)
+
+	return errs
+}
+
+func validate(lParams *LintParams) error {
+	stat, err := os.Stat(lParams.Input)
+	if err != nil {
+		return err
+	}
+
+	if stat.IsDir() {
+		return fmt.Errorf("path %s is a directory include only files", lParams.Input)
+	}
+
+	return nil
+}
+
+type linter struct {
+	issues []Issue
+}
+
+func (l *linter) errorf(check, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{Severity: SeverityError, Check: check, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) warnf(check, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{Severity: SeverityWarning, Check: check, Message: fmt.Sprintf(format, args...)})
+}
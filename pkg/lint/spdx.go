@@ -0,0 +1,116 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	liclib "github.com/interlynk-io/sbomasm/pkg/licenses"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/samber/lo"
+	"github.com/spdx/tools-golang/spdx"
+)
+
+func spdxLint(lParams *LintParams, format detect.FileFormat) ([]Issue, error) {
+	doc, err := sbom.LoadSpdx(*lParams.Ctx, lParams.Input, format, lParams.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{}
+	l.spdxDocument(doc)
+
+	return l.issues, nil
+}
+
+func (l *linter) spdxDocument(doc *spdx.Document) {
+	ids := map[spdx.ElementID]int{doc.SPDXIdentifier: 1}
+
+	for _, pkg := range doc.Packages {
+		ids[pkg.PackageSPDXIdentifier]++
+		l.spdxPackage(pkg)
+
+		// files nested in packages share the document id space
+		for _, file := range pkg.Files {
+			ids[file.FileSPDXIdentifier]++
+		}
+	}
+
+	for _, file := range doc.Files {
+		ids[file.FileSPDXIdentifier]++
+	}
+
+	for _, snippet := range doc.Snippets {
+		ids[snippet.SnippetSPDXIdentifier]++
+	}
+
+	dups := lo.Keys(lo.PickBy(ids, func(_ spdx.ElementID, n int) bool { return n > 1 }))
+	sort.Slice(dups, func(i, j int) bool { return dups[i] < dups[j] })
+	for _, id := range dups {
+		l.errorf("duplicate-ref", "spdx id %s is used by %d elements", id, ids[id])
+	}
+
+	describes := 0
+	for _, r := range doc.Relationships {
+		if r.RefA.DocumentRefID == "" && r.RefA.ElementRefID == doc.SPDXIdentifier && r.Relationship == spdx.RelationshipDescribes {
+			describes++
+		}
+		if r.RefB.DocumentRefID == "" && r.RefB.ElementRefID == doc.SPDXIdentifier && r.Relationship == spdx.RelationshipDescribedBy {
+			describes++
+		}
+
+		for _, ref := range []spdx.DocElementID{r.RefA, r.RefB} {
+			// external documents and NONE or NOASSERTION are not resolved
+			if ref.DocumentRefID != "" || ref.SpecialID != "" {
+				continue
+			}
+			if _, ok := ids[ref.ElementRefID]; !ok {
+				l.errorf("dangling-dependency", "%s relationship refers to %s which does not match any element",
+					r.Relationship, ref.ElementRefID)
+			}
+		}
+	}
+
+	if describes == 0 {
+		l.errorf("primary-component", "document does not describe any package")
+	} else if describes > 1 {
+		l.warnf("primary-component", "document describes %d packages, the primary package is ambiguous", describes)
+	}
+}
+
+func (l *linter) spdxPackage(pkg *spdx.Package) {
+	subject := fmt.Sprintf("package %s@%s", pkg.PackageName, pkg.PackageVersion)
+
+	identified := lo.SomeBy(pkg.PackageExternalReferences, func(ref *spdx.PackageExternalReference) bool {
+		return ref.RefType == spdx.PackageManagerPURL || strings.HasPrefix(ref.RefType, "cpe")
+	})
+	if !identified {
+		l.warnf("missing-identifier", "%s has no purl or cpe", subject)
+	}
+
+	for _, lic := range lo.Uniq([]string{pkg.PackageLicenseConcluded, pkg.PackageLicenseDeclared}) {
+		switch strings.ToUpper(lic) {
+		case "", "NONE", "NOASSERTION":
+			continue
+		}
+
+		if err := liclib.ValidateExpression(lic); err != nil {
+			l.errorf("invalid-license", "%s has an invalid license expression %s", subject, lic)
+		}
+	}
+}
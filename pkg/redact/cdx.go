@@ -0,0 +1,190 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"fmt"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/samber/lo"
+)
+
+func cdxRedact(r *redactor, format detect.FileFormat) error {
+	var fileFormat cydx.BOMFileFormat
+
+	switch format {
+	case detect.FileFormatJSON:
+		fileFormat = cydx.BOMFileFormatJSON
+	case detect.FileFormatXML:
+		fileFormat = cydx.BOMFileFormatXML
+	default:
+		return fmt.Errorf("unsupported cyclonedx file format %s", format)
+	}
+
+	bom, err := sbom.LoadCdx(*r.params.Ctx, r.params.Input, format, r.params.Limits)
+	if err != nil {
+		return err
+	}
+
+	r.cdxBom(bom)
+
+	// Always generate a new serial number on redact
+	bom.SerialNumber = uuid.New().URN()
+
+	out, err := r.output()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	encoder := cydx.NewBOMEncoder(out, fileFormat)
+	encoder.SetPretty(true)
+	encoder.SetEscapeHTML(true)
+
+	return encoder.Encode(bom)
+}
+
+func (r *redactor) cdxBom(bom *cydx.BOM) {
+	if bom.Metadata != nil {
+		bom.Metadata.Properties = r.cdxProperties("metadata", bom.Metadata.Properties)
+		r.cdxSupplier("metadata", bom.Metadata.Supplier)
+		r.cdxSupplier("metadata", bom.Metadata.Manufacturer)
+
+		if bom.Metadata.Component != nil {
+			r.cdxComponent(bom.Metadata.Component)
+		}
+	}
+
+	bom.ExternalReferences = r.cdxExternalReferences("document", bom.ExternalReferences)
+
+	for i := range lo.FromPtr(bom.Components) {
+		r.cdxComponent(&(*bom.Components)[i])
+	}
+
+	for i := range lo.FromPtr(bom.Services) {
+		r.cdxService(&(*bom.Services)[i])
+	}
+}
+
+func (r *redactor) cdxComponent(comp *cydx.Component) {
+	subject := fmt.Sprintf("component %s@%s", comp.Name, comp.Version)
+
+	comp.Properties = r.cdxProperties(subject, comp.Properties)
+	comp.ExternalReferences = r.cdxExternalReferences(subject, comp.ExternalReferences)
+	r.cdxSupplier(subject, comp.Supplier)
+	r.cdxSupplier(subject, comp.Manufacturer)
+
+	for i := range lo.FromPtr(comp.Components) {
+		r.cdxComponent(&(*comp.Components)[i])
+	}
+}
+
+func (r *redactor) cdxService(svc *cydx.Service) {
+	subject := fmt.Sprintf("service %s@%s", svc.Name, svc.Version)
+
+	svc.Properties = r.cdxProperties(subject, svc.Properties)
+	svc.ExternalReferences = r.cdxExternalReferences(subject, svc.ExternalReferences)
+	r.cdxSupplier(subject, svc.Provider)
+
+	if svc.Endpoints != nil {
+		endpoints := r.urlList(subject, *svc.Endpoints)
+		svc.Endpoints = &endpoints
+	}
+
+	for i := range lo.FromPtr(svc.Services) {
+		r.cdxService(&(*svc.Services)[i])
+	}
+}
+
+func (r *redactor) cdxProperties(subject string, props *[]cydx.Property) *[]cydx.Property {
+	if props == nil || len(r.params.Properties) == 0 {
+		return props
+	}
+
+	kept := lo.Reject(*props, func(p cydx.Property, _ int) bool {
+		if r.matchProperty(p.Name) {
+			r.log.Debugf("redacted property %s from %s", p.Name, subject)
+			r.properties++
+			return true
+		}
+		return false
+	})
+
+	if len(kept) == 0 {
+		return nil
+	}
+	return &kept
+}
+
+func (r *redactor) cdxExternalReferences(subject string, refs *[]cydx.ExternalReference) *[]cydx.ExternalReference {
+	if refs == nil {
+		return refs
+	}
+
+	kept := lo.Reject(*refs, func(ref cydx.ExternalReference, _ int) bool {
+		if r.matchURL(ref.URL) {
+			r.log.Debugf("redacted %s reference %s from %s", ref.Type, ref.URL, subject)
+			r.urls++
+			return true
+		}
+		return false
+	})
+
+	if len(kept) == 0 {
+		return nil
+	}
+	return &kept
+}
+
+func (r *redactor) cdxSupplier(subject string, org *cydx.OrganizationalEntity) {
+	if org == nil {
+		return
+	}
+
+	if org.URL != nil {
+		urls := r.urlList(subject, *org.URL)
+		org.URL = &urls
+		if len(urls) == 0 {
+			org.URL = nil
+		}
+	}
+
+	if !r.params.SupplierEmails {
+		return
+	}
+
+	for i := range lo.FromPtr(org.Contact) {
+		contact := &(*org.Contact)[i]
+		if contact.Email != "" {
+			r.log.Debugf("redacted supplier email %s from %s", contact.Email, subject)
+			contact.Email = ""
+			r.emails++
+		}
+	}
+}
+
+func (r *redactor) urlList(subject string, urls []string) []string {
+	return lo.Reject(urls, func(u string, _ int) bool {
+		if r.matchURL(u) {
+			r.log.Debugf("redacted url %s from %s", u, subject)
+			r.urls++
+			return true
+		}
+		return false
+	})
+}
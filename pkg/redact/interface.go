@@ -0,0 +1,117 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+)
+
+// RedactParams represents the parameters for the redact command
+type RedactParams struct {
+	Ctx *context.Context
+
+	Input  string
+	Output string
+
+	// InputFormat forces the file format of the input instead of detecting it
+	InputFormat string
+
+	// Properties are name patterns e.g "internal:*" of the properties to remove
+	Properties []string
+
+	// InternalHosts are host patterns e.g "*.corp.example.com" of the urls to remove
+	InternalHosts []string
+
+	SupplierEmails bool
+
+	Limits limits.Limits
+}
+
+func NewRedactParams() *RedactParams {
+	return &RedactParams{
+		Limits: limits.Default(),
+	}
+}
+
+func Redact(rParams *RedactParams) error {
+	log := logger.FromContext(*rParams.Ctx)
+
+	if err := validate(rParams); err != nil {
+		return err
+	}
+
+	inputFormat, err := detect.ParseFileFormat(rParams.InputFormat)
+	if err != nil {
+		return err
+	}
+
+	spec, format, err := sbom.Detect(rParams.Input, inputFormat, rParams.Limits)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("input sbom spec: %s format: %s", spec, format)
+
+	r := newRedactor(rParams)
+
+	switch spec {
+	case detect.SBOMSpecCDX:
+		err = cdxRedact(r, format)
+	case detect.SBOMSpecSPDX:
+		err = spdxRedact(r, format)
+	default:
+		err = fmt.Errorf("unsupported sbom spec %s", spec)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	r.report()
+
+	return nil
+}
+
+func validate(rParams *RedactParams) error {
+	if len(rParams.Properties) == 0 && len(rParams.InternalHosts) == 0 && !rParams.SupplierEmails {
+		return errors.New("nothing to redact, provide at least one of property, internal-host or supplier-emails")
+	}
+
+	for _, p := range append(rParams.Properties, rParams.InternalHosts...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %s: %v", p, err)
+		}
+	}
+
+	stat, err := os.Stat(rParams.Input)
+	if err != nil {
+		return err
+	}
+
+	if stat.IsDir() {
+		return fmt.Errorf("path %s is a directory include only files", rParams.Input)
+	}
+
+	return nil
+}
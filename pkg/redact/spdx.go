@@ -0,0 +1,102 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/sbom"
+	"github.com/samber/lo"
+	spdx_json "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx"
+	spdx_tv "github.com/spdx/tools-golang/tagvalue"
+	spdx_yaml "github.com/spdx/tools-golang/yaml"
+)
+
+// matches the email suffix of a supplier e.g "Interlynk (support@interlynk.io)"
+var spdxSupplierEmail = regexp.MustCompile(`\s*\([^()]*@[^()]*\)\s*$`)
+
+func spdxRedact(r *redactor, format detect.FileFormat) error {
+	switch format {
+	case detect.FileFormatJSON, detect.FileFormatTagValue, detect.FileFormatYAML:
+	default:
+		return fmt.Errorf("unsupported spdx file format %s", format)
+	}
+
+	doc, err := sbom.LoadSpdx(*r.params.Ctx, r.params.Input, format, r.params.Limits)
+	if err != nil {
+		return err
+	}
+
+	r.spdxDocument(doc)
+
+	out, err := r.output()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	switch format {
+	case detect.FileFormatJSON:
+		err = spdx_json.Write(doc, out, spdx_json.Indent(" "), spdx_json.EscapeHTML(true))
+	case detect.FileFormatTagValue:
+		err = spdx_tv.Write(doc, out)
+	case detect.FileFormatYAML:
+		err = spdx_yaml.Write(doc, out)
+	}
+
+	return err
+}
+
+func (r *redactor) spdxDocument(doc *spdx.Document) {
+	if len(r.params.Properties) > 0 {
+		r.log.Debugf("spdx documents do not carry properties, property patterns are ignored")
+	}
+
+	for _, pkg := range doc.Packages {
+		subject := fmt.Sprintf("package %s@%s", pkg.PackageName, pkg.PackageVersion)
+
+		if r.matchURL(pkg.PackageDownloadLocation) {
+			r.log.Debugf("redacted download location %s from %s", pkg.PackageDownloadLocation, subject)
+			pkg.PackageDownloadLocation = "NOASSERTION"
+			r.urls++
+		}
+
+		if r.matchURL(pkg.PackageHomePage) {
+			r.log.Debugf("redacted homepage %s from %s", pkg.PackageHomePage, subject)
+			pkg.PackageHomePage = ""
+			r.urls++
+		}
+
+		pkg.PackageExternalReferences = lo.Reject(pkg.PackageExternalReferences, func(ref *spdx.PackageExternalReference, _ int) bool {
+			// purls and cpes are not urls
+			if !strings.Contains(ref.Locator, "://") || !r.matchURL(ref.Locator) {
+				return false
+			}
+			r.log.Debugf("redacted %s reference %s from %s", ref.RefType, ref.Locator, subject)
+			r.urls++
+			return true
+		})
+
+		if r.params.SupplierEmails && pkg.PackageSupplier != nil && spdxSupplierEmail.MatchString(pkg.PackageSupplier.Supplier) {
+			r.log.Debugf("redacted supplier email from %s", subject)
+			pkg.PackageSupplier.Supplier = spdxSupplierEmail.ReplaceAllString(pkg.PackageSupplier.Supplier, "")
+			r.emails++
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Interlynk.io
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+type redactor struct {
+	params *RedactParams
+	log    *zap.SugaredLogger
+
+	properties int
+	urls       int
+	emails     int
+}
+
+func newRedactor(rParams *RedactParams) *redactor {
+	return &redactor{
+		params: rParams,
+		log:    logger.FromContext(*rParams.Ctx),
+	}
+}
+
+func (r *redactor) matchProperty(name string) bool {
+	return lo.SomeBy(r.params.Properties, func(p string) bool {
+		ok, _ := path.Match(p, name)
+		return ok
+	})
+}
+
+// matchURL reports whether the url points to one of the internal hosts. Urls
+// without a scheme e.g "git.corp.example.com/repo" and scp like git urls
+// e.g "git@git.corp.example.com:org/repo" are supported.
+func (r *redactor) matchURL(u string) bool {
+	if len(r.params.InternalHosts) == 0 || u == "" {
+		return false
+	}
+
+	host := urlHost(u)
+	if host == "" {
+		return false
+	}
+
+	return lo.SomeBy(r.params.InternalHosts, func(p string) bool {
+		ok, _ := path.Match(strings.ToLower(p), host)
+		return ok
+	})
+}
+
+func urlHost(u string) string {
+	u = strings.TrimSpace(u)
+
+	if !strings.Contains(u, "://") {
+		if at := strings.Index(u, "@"); at >= 0 && strings.Contains(u[at:], ":") {
+			u = strings.Replace(u[at+1:], ":", "/", 1)
+		}
+		u = "https://" + u
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(parsed.Hostname())
+}
+
+func (r *redactor) report() {
+	r.log.Infof("redacted %d properties, %d internal urls and %d supplier emails", r.properties, r.urls, r.emails)
+}
+
+func (r *redactor) output() (io.WriteCloser, error) {
+	if r.params.Output == "" {
+		return os.Stdout, nil
+	}
+	return os.Create(r.params.Output)
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sbom holds the loaders shared by the commands reading sboms, so
+// format detection, --input-format and the limits behave the same in each.
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cydx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
+	"github.com/interlynk-io/sbomasm/pkg/logger"
+	spdx_json "github.com/spdx/tools-golang/json"
+	spdx_rdf "github.com/spdx/tools-golang/rdf"
+	"github.com/spdx/tools-golang/spdx"
+	spdx_tv "github.com/spdx/tools-golang/tagvalue"
+	spdx_yaml "github.com/spdx/tools-golang/yaml"
+)
+
+// Detect returns the spec and file format of the sbom at path, format
+// overrides the detected file format when set. The file size limit is checked
+// before detection reads the file.
+func Detect(path string, format detect.FileFormat, l limits.Limits) (detect.SBOMSpecFormat, detect.FileFormat, error) {
+	if err := l.CheckFileSize(path); err != nil {
+		return "", "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	return detect.DetectWithFormat(f, format)
+}
+
+// LoadCdx reads the cyclonedx bom at path, format overrides the detected
+// file format when set.
+func LoadCdx(ctx context.Context, path string, format detect.FileFormat, l limits.Limits) (*cydx.BOM, error) {
+	log := logger.FromContext(ctx)
+
+	spec, format, err := Detect(path, format, l)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("loading bom:%s spec:%s format:%s", path, spec, format)
+
+	var fileFormat cydx.BOMFileFormat
+
+	switch format {
+	case detect.FileFormatJSON:
+		fileFormat = cydx.BOMFileFormatJSON
+	case detect.FileFormatXML:
+		fileFormat = cydx.BOMFileFormatXML
+	default:
+		return nil, fmt.Errorf("unsupported cyclonedx file format %s", format)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bom := new(cydx.BOM)
+	if err := cydx.NewBOMDecoder(f, fileFormat).Decode(bom); err != nil {
+		return nil, fmt.Errorf("decoding %s as %s: %w", filepath.Base(path), format, err)
+	}
+
+	if err := l.CheckComponents(path, limits.CountComponents(bom.Components)); err != nil {
+		return nil, err
+	}
+
+	return bom, nil
+}
+
+// LoadSpdx reads the spdx document at path, format overrides the detected
+// file format when set.
+func LoadSpdx(ctx context.Context, path string, format detect.FileFormat, l limits.Limits) (*spdx.Document, error) {
+	log := logger.FromContext(ctx)
+
+	spec, format, err := Detect(path, format, l)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("loading bom:%s spec:%s format:%s", path, spec, format)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var doc *spdx.Document
+
+	switch format {
+	case detect.FileFormatJSON:
+		doc, err = spdx_json.Read(f)
+	case detect.FileFormatTagValue:
+		doc, err = spdx_tv.Read(f)
+	case detect.FileFormatYAML:
+		doc, err = spdx_yaml.Read(f)
+	case detect.FileFormatRDF:
+		doc, err = spdx_rdf.Read(f)
+	default:
+		return nil, fmt.Errorf("unsupported spdx file format %s", format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s as %s: %w", filepath.Base(path), format, err)
+	}
+
+	if err := l.CheckComponents(path, len(doc.Packages)+len(doc.Files)); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sbom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/interlynk-io/sbomasm/pkg/detect"
+	"github.com/interlynk-io/sbomasm/pkg/limits"
+)
+
+const cdxJSON = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "components": [
+    {"type": "library", "name": "a", "components": [{"type": "library", "name": "a1"}]}
+  ]
+}`
+
+const spdxJSON = `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "doc",
+  "documentNamespace": "https://example.com/doc",
+  "creationInfo": {"created": "2024-01-02T15:04:05Z", "creators": ["Tool: test"]},
+  "packages": [
+    {"SPDXID": "SPDXRef-a", "name": "a", "downloadLocation": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-b", "name": "b", "downloadLocation": "NOASSERTION"}
+  ]
+}`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDetect(t *testing.T) {
+	cdx := writeFile(t, "in.cdx.json", cdxJSON)
+	big := writeFile(t, "big.cdx.json", cdxJSON+strings.Repeat(" ", 1<<20))
+
+	tests := []struct {
+		name       string
+		path       string
+		format     detect.FileFormat
+		limits     limits.Limits
+		wantSpec   detect.SBOMSpecFormat
+		wantFormat detect.FileFormat
+		wantErr    string
+	}{
+		{"detected", cdx, detect.FileFormatUnknown, limits.Default(), detect.SBOMSpecCDX, detect.FileFormatJSON, ""},
+		{"forced format", cdx, detect.FileFormatXML, limits.Default(), detect.SBOMSpecCDX, detect.FileFormatXML, ""},
+		{"over the file size limit", big, detect.FileFormatUnknown, limits.Limits{MaxFileSizeMB: 1}, "", "", "exceeds the limit of 1 MB"},
+		{"missing file", filepath.Join(t.TempDir(), "missing.json"), detect.FileFormatUnknown, limits.Limits{}, "", "", "no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, format, err := Detect(tt.path, tt.format, tt.limits)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Detect() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Detect() unexpected error %v", err)
+			}
+			if spec != tt.wantSpec || format != tt.wantFormat {
+				t.Errorf("Detect() = %s, %s, want %s, %s", spec, format, tt.wantSpec, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestLoadCdx(t *testing.T) {
+	cdx := writeFile(t, "in.cdx.json", cdxJSON)
+
+	tests := []struct {
+		name    string
+		format  detect.FileFormat
+		limits  limits.Limits
+		wantErr string
+	}{
+		{"loaded", detect.FileFormatUnknown, limits.Default(), ""},
+		{"nested components are counted", detect.FileFormatUnknown, limits.Limits{MaxComponents: 1}, "has 2 components which exceeds the limit of 1"},
+		{"decode error names the file and format", detect.FileFormatXML, limits.Default(), "decoding in.cdx.json as xml: EOF"},
+		{"spdx only format", detect.FileFormatTagValue, limits.Default(), "unsupported cyclonedx file format tag-value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bom, err := LoadCdx(context.Background(), cdx, tt.format, tt.limits)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadCdx() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadCdx() unexpected error %v", err)
+			}
+			if limits.CountComponents(bom.Components) != 2 {
+				t.Errorf("LoadCdx() loaded %d components, want 2", limits.CountComponents(bom.Components))
+			}
+		})
+	}
+}
+
+func TestLoadSpdx(t *testing.T) {
+	doc := writeFile(t, "in.spdx.json", spdxJSON)
+
+	tests := []struct {
+		name    string
+		format  detect.FileFormat
+		limits  limits.Limits
+		wantErr string
+	}{
+		{"loaded", detect.FileFormatUnknown, limits.Default(), ""},
+		{"packages are counted", detect.FileFormatUnknown, limits.Limits{MaxComponents: 1}, "has 2 components which exceeds the limit of 1"},
+		{"decode error names the file and format", detect.FileFormatTagValue, limits.Default(), "decoding in.spdx.json as tag-value: "},
+		{"cyclonedx only format", detect.FileFormatXML, limits.Default(), "unsupported spdx file format xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadSpdx(context.Background(), doc, tt.format, tt.limits)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadSpdx() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSpdx() unexpected error %v", err)
+			}
+			if len(got.Packages) != 2 {
+				t.Errorf("LoadSpdx() loaded %d packages, want 2", len(got.Packages))
+			}
+		})
+	}
+}
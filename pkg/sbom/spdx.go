@@ -0,0 +1,50 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sbom
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spdx/tools-golang/spdx"
+)
+
+// FallbackPrimaryPackage is used when the document has no DESCRIBES relationship,
+// which happens when sboms lose it during a round-trip through other tools.
+// In order of preference it picks the root package created by sbomasm assemble,
+// the only package declaring a primary package purpose, or the only package.
+func FallbackPrimaryPackage(doc *spdx.Document) *spdx.Package {
+	for _, pkg := range doc.Packages {
+		if strings.HasPrefix(string(pkg.PackageSPDXIdentifier), "RootPackage-") {
+			return pkg
+		}
+	}
+
+	withPurpose := lo.Filter(doc.Packages, func(pkg *spdx.Package, _ int) bool {
+		return pkg.PrimaryPackagePurpose != ""
+	})
+
+	if len(withPurpose) == 1 {
+		return withPurpose[0]
+	}
+
+	if len(doc.Packages) == 1 {
+		return doc.Packages[0]
+	}
+
+	return nil
+}
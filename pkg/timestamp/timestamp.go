@@ -0,0 +1,84 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timestamp resolves the timestamp of an assembled sbom, shared by
+// the cyclonedx and spdx mergers.
+package timestamp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timestamp of the assembled sbom, besides these an RFC3339 value is used as is.
+const (
+	Now  = "now"
+	Keep = "keep"
+)
+
+// Normalize validates a timestamp setting, now and keep are lower cased and
+// an RFC3339 value is converted to UTC.
+func Normalize(value string) (string, error) {
+	switch v := strings.ToLower(value); v {
+	case "", Now, Keep:
+		return v, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("unsupported output timestamp %s :: use now, keep or an RFC3339 value e.g 2024-01-02T15:04:05Z", value)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// Resolve returns the timestamp for a normalized setting, the current time by
+// default, the latest of the input timestamps for keep or the fixed value.
+// It returns false when keep falls back to the current time because none of
+// the input timestamps parse.
+func Resolve(setting string, inputs []string) (string, bool) {
+	switch setting {
+	case "", Now:
+		return now(), true
+	case Keep:
+		if latest, ok := Latest(inputs); ok {
+			return latest, true
+		}
+		return now(), false
+	}
+	return setting, true
+}
+
+// Latest returns the latest of the RFC3339 timestamps in UTC, values which
+// do not parse are ignored.
+func Latest(values []string) (string, bool) {
+	var latest time.Time
+	for _, v := range values {
+		t, err := time.Parse(time.RFC3339, v)
+		if err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+
+	if latest.IsZero() {
+		return "", false
+	}
+	return latest.UTC().Format(time.RFC3339), true
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
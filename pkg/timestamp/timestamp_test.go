@@ -0,0 +1,122 @@
+// Copyright 2023 Interlynk.io
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timestamp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"unset", "", "", ""},
+		{"now", "NOW", Now, ""},
+		{"keep", "Keep", Keep, ""},
+		{"utc", "2024-01-02T15:04:05Z", "2024-01-02T15:04:05Z", ""},
+		{"offset converted to utc", "2024-01-02T15:04:05+01:00", "2024-01-02T14:04:05Z", ""},
+		{"invalid", "yesterday", "", "unsupported output timestamp yesterday"},
+		{"not rfc3339", "2024-01-02 15:04:05", "", "unsupported output timestamp 2024-01-02 15:04:05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Normalize(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize(%q) unexpected error %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+		wantOk bool
+	}{
+		{"none", nil, "", false},
+		{"invalid only", []string{"", "not a time"}, "", false},
+		{"latest in utc", []string{"2024-01-01T10:00:00Z", "2024-03-05T14:00:00+02:00", "2024-02-01T00:00:00Z"}, "2024-03-05T12:00:00Z", true},
+		{"invalid ignored", []string{"not a time", "2024-01-01T10:00:00Z"}, "2024-01-01T10:00:00Z", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Latest(tt.values)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Latest(%q) = %q, %v, want %q, %v", tt.values, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	inputs := []string{"2024-01-01T10:00:00Z", "2024-03-05T14:00:00+02:00"}
+
+	tests := []struct {
+		name    string
+		setting string
+		inputs  []string
+		want    string
+		wantOk  bool
+	}{
+		{"keep", Keep, inputs, "2024-03-05T12:00:00Z", true},
+		{"fixed", "2024-01-02T14:04:05Z", inputs, "2024-01-02T14:04:05Z", true},
+		{"default is now", "", inputs, "", true},
+		{"now", Now, inputs, "", true},
+		{"keep falls back to now", Keep, []string{"not a time"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now().UTC().Truncate(time.Second)
+			got, ok := Resolve(tt.setting, tt.inputs)
+			if ok != tt.wantOk {
+				t.Fatalf("Resolve(%q) ok = %v, want %v", tt.setting, ok, tt.wantOk)
+			}
+
+			if tt.want != "" {
+				if got != tt.want {
+					t.Errorf("Resolve(%q) = %q, want %q", tt.setting, got, tt.want)
+				}
+				return
+			}
+
+			// the current time
+			ts, err := time.Parse(time.RFC3339, got)
+			if err != nil || ts.Before(before) || !strings.HasSuffix(got, "Z") {
+				t.Errorf("Resolve(%q) = %q, want the current time in UTC", tt.setting, got)
+			}
+		})
+	}
+}